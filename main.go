@@ -1,7 +1,12 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/yourusername/file-storage-api/src/config"
 	"github.com/yourusername/file-storage-api/src/models"
@@ -23,8 +28,26 @@ func main() {
 
 	// Initialize file collection
 	models.InitFileCollection()
-	// Initialize file collection
-	models.InitFileCollection()
+	// Initialize upload session collection
+	models.InitUploadSessionCollection()
+	// Initialize blob collection
+	models.InitBlobCollection()
+	// Initialize share collection
+	models.InitShareCollection()
+	// Initialize refresh token collection
+	models.InitRefreshTokenCollection()
+	// Initialize API key collection
+	models.InitAPIKeyCollection()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	// Periodically purge expired share links
+	go models.StartShareSweeper(time.Hour)
+	// Periodically purge expired upload sessions and their staging files
+	go models.StartUploadSessionJanitor(time.Hour)
 
 	// Create file service
 	fileService, err := storage.NewFileService()
@@ -42,3 +65,46 @@ func main() {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// runMigrate streams every file off the storage driver configured in
+// config.yml onto a new backend, e.g.:
+//
+//	file-storage-api migrate --to=s3 --config bucket=my-bucket --config region=us-east-1
+func runMigrate(args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	to := fs.String("to", "", "name of the storage driver to migrate files onto (s3, gridfs, gcs, ...)")
+	var rawConfig configValues
+	fs.Var(&rawConfig, "config", "key=value pair for the destination driver's config; may be repeated")
+	_ = fs.Parse(args)
+
+	if *to == "" {
+		log.Fatalf("migrate: --to is required")
+	}
+
+	toDriver, err := storage.NewDriver(*to, rawConfig.toMap())
+	if err != nil {
+		log.Fatalf("migrate: could not initialize destination driver %q: %v", *to, err)
+	}
+
+	if err := storage.Migrate(context.Background(), *to, toDriver); err != nil {
+		log.Fatalf("migrate: %v", err)
+	}
+}
+
+// configValues collects repeated -config key=value flags into a map.
+type configValues map[string]interface{}
+
+func (c *configValues) String() string { return "" }
+
+func (c *configValues) Set(pair string) error {
+	if *c == nil {
+		*c = configValues{}
+	}
+	key, value, _ := strings.Cut(pair, "=")
+	(*c)[key] = value
+	return nil
+}
+
+func (c configValues) toMap() map[string]interface{} {
+	return map[string]interface{}(c)
+}
@@ -1,6 +1,10 @@
 package auth
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"time"
 
@@ -40,7 +44,7 @@ func GenerateToken(user models.User) (string, error) {
 	}
 
 	// Calculate expiration time
-	expirationTime := time.Now().Add(time.Duration(config.AppConfig.JWT.ExpirationHours) * time.Hour)
+	expirationTime := time.Now().Add(config.GetJWTExpirationHours())
 
 	// Create the Claims
 	claims := &Claims{
@@ -63,6 +67,72 @@ func GenerateToken(user models.User) (string, error) {
 	return tokenString, nil
 }
 
+// GenerateAccessToken generates a short-lived JWT access token for a user,
+// meant to be paired with a refresh token rather than used on its own for
+// long-lived sessions.
+func GenerateAccessToken(user models.User) (string, error) {
+	jwtSecret := config.GetJWTSecret()
+	if jwtSecret == "" {
+		return "", errors.New("JWT secret not configured")
+	}
+
+	expirationTime := time.Now().Add(config.GetAccessTokenTTL())
+
+	claims := &Claims{
+		UserID: user.ID.Hex(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(jwtSecret))
+}
+
+// GenerateRefreshToken creates a new opaque refresh token, returning both
+// the plaintext value (sent to the client once) and the hash that should be
+// persisted in its place.
+func GenerateRefreshToken() (plaintext, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	plaintext = base64.RawURLEncoding.EncodeToString(buf)
+	return plaintext, HashRefreshToken(plaintext), nil
+}
+
+// HashRefreshToken hashes a plaintext refresh token for storage/lookup.
+// Unlike passwords, refresh tokens are high-entropy random values compared
+// for exact equality, so a fast, indexable hash (rather than bcrypt) is
+// appropriate here.
+func HashRefreshToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// GenerateAPIKey creates a new opaque API key, returning both the plaintext
+// value (shown to the client once) and the hash that should be persisted in
+// its place.
+func GenerateAPIKey() (plaintext, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	plaintext = "sk_" + base64.RawURLEncoding.EncodeToString(buf)
+	return plaintext, HashAPIKey(plaintext), nil
+}
+
+// HashAPIKey hashes a plaintext API key for storage/lookup, for the same
+// reason refresh tokens are hashed rather than bcrypted: it's a high-entropy
+// random value compared for exact equality, not a human-chosen password.
+func HashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
 // ValidateToken validates a JWT token and returns the user ID
 func ValidateToken(tokenString string) (primitive.ObjectID, error) {
 	// Get JWT secret
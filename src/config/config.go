@@ -3,31 +3,42 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
-	"log"
 	"math"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"file-storage-api/src/logging"
 )
 
 // Config holds all application configuration
 type Config struct {
 	Server struct {
-		Port int    `yaml:"port"`
-		Host string `yaml:"host"`
+		Port                  int    `yaml:"port"`
+		Host                  string `yaml:"host"`
+		RequestTimeoutSeconds int    `yaml:"request_timeout_seconds"`
 	} `yaml:"server"`
 
 	JWT struct {
-		Secret          string `yaml:"secret"`
-		ExpirationHours int    `yaml:"expiration_hours"`
+		Secret             string `yaml:"secret"`
+		ExpirationHours    int    `yaml:"expiration_hours"`
+		AccessTokenMinutes int    `yaml:"access_token_minutes"`
+		RefreshTokenDays   int    `yaml:"refresh_token_days"`
 	} `yaml:"jwt"`
 	Storage struct {
-		Path        string `yaml:"path"`
-		MaxPerUser  int64  `yaml:"max_per_user"`
-		DisplayInMB bool   `yaml:"display_in_mb"`
+		Path         string                 `yaml:"path"`
+		Driver       string                 `yaml:"driver"`
+		DriverConfig map[string]interface{} `yaml:"driver_config"`
+		MaxPerUser   int64                  `yaml:"max_per_user"`
+		DisplayInMB  bool                   `yaml:"display_in_mb"`
 	} `yaml:"storage"`
 
+	Auth struct {
+		Providers []string `yaml:"providers"`
+	} `yaml:"auth"`
+
 	MongoDB struct {
 		URI         string `yaml:"uri"`
 		Database    string `yaml:"database"`
@@ -36,129 +47,304 @@ type Config struct {
 			Files string `yaml:"files"`
 		} `yaml:"collections"`
 	} `yaml:"mongodb"`
+
+	Logging struct {
+		Level  string `yaml:"level"`
+		Format string `yaml:"format"`
+		Output string `yaml:"output"`
+	} `yaml:"logging"`
+
+	Upload struct {
+		MaxChunkSize      int64 `yaml:"max_chunk_size"`
+		MaxInflightChunks int   `yaml:"max_inflight_chunks"`
+	} `yaml:"upload"`
 }
 
-// Global configuration
+// Global configuration. Kept for backward compatibility with code and tests
+// that assign/read it directly; LoadConfig keeps it in sync with whatever
+// the active Manager (if any) currently holds. Getters prefer the Manager's
+// atomically-swapped snapshot so concurrent reloads can't race a reader
+// mid-struct-copy.
 var AppConfig Config
 
-// LoadConfig loads application configuration from YAML file
-func LoadConfig(configPath string) error {
-	// Use default config path if not provided
-	if configPath == "" {
-		configPath = "config.yml"
+// current returns the Config snapshot getters should read from: the active
+// Manager's snapshot if hot-reloading was started via LoadConfig/NewManager,
+// or the plain AppConfig var otherwise (e.g. tests that set it directly).
+func current() *Config {
+	if manager != nil {
+		return manager.Current()
 	}
+	return &AppConfig
+}
 
-	// Read config file
-	data, err := ioutil.ReadFile(configPath)
-	if err != nil {
-		return fmt.Errorf("error reading config file: %v", err)
+// parseConfig parses raw YAML bytes into a Config, filling in defaults for
+// anything left unset. It fails only on malformed YAML; the values
+// themselves (driver names, auth providers, ports, ...) are not checked. It
+// never mutates global state, so it's safe to use for both the initial load
+// and later hot-reloads.
+func parseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config: %v", err)
 	}
 
-	// Parse YAML
-	if err := yaml.Unmarshal(data, &AppConfig); err != nil {
-		return fmt.Errorf("error parsing config: %v", err)
+	if cfg.Server.Port == 0 {
+		cfg.Server.Port = 8080
 	}
 
-	// Create storage directory if it doesn't exist
-	if err := os.MkdirAll(AppConfig.Storage.Path, 0755); err != nil {
-		return fmt.Errorf("could not create storage directory: %v", err)
+	if cfg.Server.Host == "" {
+		cfg.Server.Host = "localhost"
+	}
+
+	if cfg.Server.RequestTimeoutSeconds == 0 {
+		cfg.Server.RequestTimeoutSeconds = 30
 	}
 
-	// Set defaults if values are missing
-	if AppConfig.Server.Port == 0 {
-		AppConfig.Server.Port = 8080
+	if cfg.JWT.Secret == "" {
+		logging.Logger.Warn("JWT secret not set, using an insecure default; do not use this in production")
+		cfg.JWT.Secret = "default-insecure-jwt-secret-change-in-production"
 	}
 
-	if AppConfig.Server.Host == "" {
-		AppConfig.Server.Host = "localhost"
+	if cfg.JWT.ExpirationHours == 0 {
+		cfg.JWT.ExpirationHours = 24
 	}
 
-	if AppConfig.JWT.Secret == "" {
-		log.Println("Warning: JWT_SECRET not set. Using a default value. This is not secure for production.")
-		AppConfig.JWT.Secret = "default-insecure-jwt-secret-change-in-production"
+	if cfg.JWT.AccessTokenMinutes == 0 {
+		cfg.JWT.AccessTokenMinutes = 15
 	}
 
-	if AppConfig.JWT.ExpirationHours == 0 {
-		AppConfig.JWT.ExpirationHours = 24
+	if cfg.JWT.RefreshTokenDays == 0 {
+		cfg.JWT.RefreshTokenDays = 30
 	}
 
-	if AppConfig.Storage.MaxPerUser == 0 {
-		AppConfig.Storage.MaxPerUser = 104857600 // 100MB default
+	if cfg.Storage.MaxPerUser == 0 {
+		cfg.Storage.MaxPerUser = 104857600 // 100MB default
 	}
 
-	if AppConfig.Storage.Path == "" {
+	if cfg.Storage.Path == "" {
 		// Default to ./storage
-		AppConfig.Storage.Path = filepath.Join(".", "storage")
+		cfg.Storage.Path = filepath.Join(".", "storage")
+	}
+
+	if cfg.Storage.Driver == "" {
+		cfg.Storage.Driver = "localfs"
+	}
+
+	if len(cfg.Auth.Providers) == 0 {
+		// Matches the JWT-with-cookie-fallback behavior the API shipped
+		// with before auth providers became configurable.
+		cfg.Auth.Providers = []string{"jwt", "cookie"}
+	}
+
+	if cfg.Storage.DriverConfig == nil {
+		cfg.Storage.DriverConfig = map[string]interface{}{}
+	}
+
+	// The localfs driver reads its root from "path"; fall back to the
+	// top-level storage path so existing configs keep working unchanged.
+	if cfg.Storage.Driver == "localfs" {
+		if _, ok := cfg.Storage.DriverConfig["path"]; !ok {
+			cfg.Storage.DriverConfig["path"] = cfg.Storage.Path
+		}
 	}
 
 	// MongoDB defaults
-	if AppConfig.MongoDB.URI == "" {
-		AppConfig.MongoDB.URI = "mongodb://localhost:27017"
+	if cfg.MongoDB.URI == "" {
+		cfg.MongoDB.URI = "mongodb://localhost:27017"
+	}
+
+	if cfg.MongoDB.Database == "" {
+		cfg.MongoDB.Database = "file_storage_api"
+	}
+
+	if cfg.MongoDB.Collections.Users == "" {
+		cfg.MongoDB.Collections.Users = "users"
+	}
+
+	if cfg.MongoDB.Collections.Files == "" {
+		cfg.MongoDB.Collections.Files = "files"
+	}
+
+	if cfg.Logging.Level == "" {
+		cfg.Logging.Level = "info"
+	}
+
+	if cfg.Logging.Format == "" {
+		cfg.Logging.Format = "text"
+	}
+
+	if cfg.Logging.Output == "" {
+		cfg.Logging.Output = "stdout"
 	}
 
-	if AppConfig.MongoDB.Database == "" {
-		AppConfig.MongoDB.Database = "file_storage_api"
+	if cfg.Upload.MaxChunkSize == 0 {
+		cfg.Upload.MaxChunkSize = 8 << 20 // 8MB default
 	}
 
-	if AppConfig.MongoDB.Collections.Users == "" {
-		AppConfig.MongoDB.Collections.Users = "users"
+	if cfg.Upload.MaxInflightChunks == 0 {
+		cfg.Upload.MaxInflightChunks = 4
 	}
 
-	if AppConfig.MongoDB.Collections.Files == "" {
-		AppConfig.MongoDB.Collections.Files = "files"
+	return &cfg, nil
+}
+
+// parseConfigFile reads and parses the YAML file at path
+func parseConfigFile(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %v", err)
 	}
+	return parseConfig(data)
+}
+
+// LoadConfig loads application configuration from a YAML file and starts
+// watching it for changes. Subsequent edits to the file are picked up
+// automatically; see Manager for the reload/validate/notify mechanics.
+func LoadConfig(configPath string) error {
+	if configPath == "" {
+		configPath = "config.yml"
+	}
+
+	cfg, err := parseConfigFile(configPath)
+	if err != nil {
+		return err
+	}
+
+	// Create storage directory if it doesn't exist
+	if err := os.MkdirAll(cfg.Storage.Path, 0755); err != nil {
+		return fmt.Errorf("could not create storage directory: %v", err)
+	}
+
+	AppConfig = *cfg
+
+	if err := logging.Init(cfg.Logging.Level, cfg.Logging.Format, cfg.Logging.Output); err != nil {
+		return fmt.Errorf("could not initialize logger: %v", err)
+	}
+
+	m, err := newManager(configPath, cfg)
+	if err != nil {
+		return err
+	}
+	manager = m
 
 	return nil
 }
 
 // GetServerAddress returns the formatted server address (host:port)
 func GetServerAddress() string {
-	return fmt.Sprintf("%s:%d", AppConfig.Server.Host, AppConfig.Server.Port)
+	c := current()
+	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
+}
+
+// GetRequestTimeout returns how long a single request is allowed to run
+// before its context is cancelled
+func GetRequestTimeout() time.Duration {
+	return time.Duration(current().Server.RequestTimeoutSeconds) * time.Second
 }
 
 // GetStoragePath returns the configured storage path
 func GetStoragePath() string {
-	return AppConfig.Storage.Path
+	return current().Storage.Path
+}
+
+// GetAuthProviders returns the ordered list of auth provider names ("jwt",
+// "cookie", "api_key") that requests are authenticated against
+func GetAuthProviders() []string {
+	return current().Auth.Providers
+}
+
+// GetStorageDriver returns the configured storage backend name (e.g. "localfs", "s3", "gcs")
+func GetStorageDriver() string {
+	return current().Storage.Driver
+}
+
+// GetStorageDriverConfig returns the driver-specific configuration block
+func GetStorageDriverConfig() map[string]interface{} {
+	return current().Storage.DriverConfig
 }
 
 // GetJWTSecret returns the configured JWT secret
 func GetJWTSecret() string {
-	return AppConfig.JWT.Secret
+	return current().JWT.Secret
+}
+
+// GetJWTExpirationHours returns how long the legacy, single-token JWT flow's
+// tokens stay valid
+func GetJWTExpirationHours() time.Duration {
+	return time.Duration(current().JWT.ExpirationHours) * time.Hour
+}
+
+// GetAccessTokenTTL returns how long a bearer access token stays valid
+func GetAccessTokenTTL() time.Duration {
+	return time.Duration(current().JWT.AccessTokenMinutes) * time.Minute
+}
+
+// GetRefreshTokenTTL returns how long a refresh token stays valid
+func GetRefreshTokenTTL() time.Duration {
+	return time.Duration(current().JWT.RefreshTokenDays) * 24 * time.Hour
 }
 
 // GetMaxStoragePerUser returns the maximum storage allowed per user
 func GetMaxStoragePerUser() int64 {
-	return AppConfig.Storage.MaxPerUser
+	return current().Storage.MaxPerUser
 }
 
 // GetMongoDBURI returns the MongoDB connection URI
 func GetMongoDBURI() string {
-	return AppConfig.MongoDB.URI
+	return current().MongoDB.URI
 }
 
 // GetMongoDBName returns the MongoDB database name
 func GetMongoDBName() string {
-	return AppConfig.MongoDB.Database
+	return current().MongoDB.Database
 }
 
 // GetMongoDBUsersCollection returns the MongoDB users collection name
 func GetMongoDBUsersCollection() string {
-	return AppConfig.MongoDB.Collections.Users
+	return current().MongoDB.Collections.Users
 }
 
 // GetMongoDBFilesCollection returns the MongoDB files collection name
 func GetMongoDBFilesCollection() string {
-	return AppConfig.MongoDB.Collections.Files
+	return current().MongoDB.Collections.Files
 }
 
 // GetDisplayInMB returns whether storage should be displayed in MB
 func GetDisplayInMB() bool {
-	return AppConfig.Storage.DisplayInMB
+	return current().Storage.DisplayInMB
+}
+
+// GetLogLevel returns the configured minimum log level ("debug", "info", "warn", "error")
+func GetLogLevel() string {
+	return current().Logging.Level
+}
+
+// GetLogFormat returns the configured log encoding ("json" or "text")
+func GetLogFormat() string {
+	return current().Logging.Format
+}
+
+// GetLogOutput returns the configured log destination ("stdout", "stderr", or a file path)
+func GetLogOutput() string {
+	return current().Logging.Output
+}
+
+// GetMaxChunkSize returns the largest chunk a client may upload in one
+// PUT /files/uploads/:id/chunks/:index request
+func GetMaxChunkSize() int64 {
+	return current().Upload.MaxChunkSize
+}
+
+// GetMaxInflightChunks returns how many chunk uploads may be in flight
+// concurrently for a single upload session
+func GetMaxInflightChunks() int {
+	return current().Upload.MaxInflightChunks
 }
 
 // FormatStorageSize formats storage size according to configuration (MB or bytes)
 func FormatStorageSize(sizeInBytes int64) float64 {
-	if AppConfig.Storage.DisplayInMB {
+	c := current()
+	if c.Storage.DisplayInMB {
 		// Convert bytes to MB and round to 2 decimal places
 		mbValue := float64(sizeInBytes) / 1024 / 1024
 		return math.Round(mbValue*100) / 100 // Round to 2 decimal places
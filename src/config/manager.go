@@ -0,0 +1,173 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/fsnotify/fsnotify"
+
+	"file-storage-api/src/logging"
+)
+
+// manager is the process-wide hot-reload manager, set by LoadConfig. It is
+// nil until LoadConfig runs (e.g. in tests that assign AppConfig directly),
+// in which case current() falls back to reading AppConfig.
+var manager *Manager
+
+// Manager owns a Config that can be safely read from many goroutines while
+// being swapped out underneath them as config.yml changes on disk.
+type Manager struct {
+	path string
+	snap atomic.Value // holds *Config
+
+	mu          sync.Mutex
+	subscribers []func(old, new *Config)
+	watcher     *fsnotify.Watcher
+}
+
+// newManager builds a Manager around an already-parsed initial Config and
+// starts watching path for changes.
+func newManager(path string, initial *Config) (*Manager, error) {
+	m := &Manager{path: path}
+	m.snap.Store(initial)
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		// Hot-reload is a nice-to-have; don't fail startup if the platform
+		// can't give us a watcher.
+		logging.Logger.Warn("config: file watching disabled, could not create watcher", "error", err)
+		return m, nil
+	}
+	m.watcher = watcher
+
+	if err := watcher.Add(path); err != nil {
+		logging.Logger.Warn("config: file watching disabled, could not watch file", "path", path, "error", err)
+		watcher.Close()
+		m.watcher = nil
+		return m, nil
+	}
+
+	go m.watch()
+
+	return m, nil
+}
+
+// Current returns the Config snapshot currently in effect. The returned
+// pointer must be treated as read-only; a reload swaps in a new one rather
+// than mutating it in place.
+func (m *Manager) Current() *Config {
+	return m.snap.Load().(*Config)
+}
+
+// Subscribe registers fn to be called after every successful reload, with
+// the config as it was before and after the change. fn is not called for
+// the initial load, only for subsequent reloads.
+func (m *Manager) Subscribe(fn func(old, new *Config)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// watch reacts to filesystem events on the watched config file, reloading
+// on writes and re-adding the watch on editors that replace the file via
+// rename (e.g. vim, many config-management tools).
+func (m *Manager) watch() {
+	for {
+		select {
+		case event, ok := <-m.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+				m.reload()
+			}
+			if event.Op&fsnotify.Remove != 0 {
+				_ = m.watcher.Add(m.path)
+			}
+		case err, ok := <-m.watcher.Errors:
+			if !ok {
+				return
+			}
+			logging.Logger.Error("config: watcher error", "error", err)
+		}
+	}
+}
+
+// reload re-parses the config file and, if it can be read and parsed,
+// atomically swaps it in and notifies subscribers. A bad reload (unreadable
+// file or malformed YAML) is logged and discarded, leaving the running
+// config untouched. Field values are not checked beyond what parseConfig
+// does, so well-formed YAML with unusable values is swapped in as is.
+func (m *Manager) reload() {
+	next, err := parseConfigFile(m.path)
+	if err != nil {
+		logging.Logger.Error("config: reload failed, keeping previous config", "error", err)
+		return
+	}
+
+	old := m.Current()
+	m.snap.Store(next)
+	AppConfig = *next
+
+	if old.Logging != next.Logging {
+		if err := logging.Init(next.Logging.Level, next.Logging.Format, next.Logging.Output); err != nil {
+			logging.Logger.Error("config: reload could not apply new logging settings", "error", err)
+		}
+	}
+
+	logging.Logger.Info("config: reloaded", "path", m.path, "changes", describeChanges(old, next))
+
+	m.mu.Lock()
+	subscribers := append([]func(old, new *Config){}, m.subscribers...)
+	m.mu.Unlock()
+
+	for _, fn := range subscribers {
+		fn(old, next)
+	}
+}
+
+// describeChanges summarizes, at a glance, which top-level settings changed
+// between two reloads for the log line in reload().
+func describeChanges(old, new *Config) string {
+	changed := []string{}
+
+	if old.Server != new.Server {
+		changed = append(changed, "server")
+	}
+	if old.JWT != new.JWT {
+		changed = append(changed, "jwt")
+	}
+	if !reflect.DeepEqual(old.Storage, new.Storage) {
+		changed = append(changed, "storage")
+	}
+	if len(old.Auth.Providers) != len(new.Auth.Providers) {
+		changed = append(changed, "auth")
+	} else {
+		for i := range old.Auth.Providers {
+			if old.Auth.Providers[i] != new.Auth.Providers[i] {
+				changed = append(changed, "auth")
+				break
+			}
+		}
+	}
+	if old.MongoDB != new.MongoDB {
+		changed = append(changed, "mongodb")
+	}
+	if old.Logging != new.Logging {
+		changed = append(changed, "logging")
+	}
+	if old.Upload != new.Upload {
+		changed = append(changed, "upload")
+	}
+
+	if len(changed) == 0 {
+		return "no effective changes"
+	}
+
+	result := changed[0]
+	for _, c := range changed[1:] {
+		result += ", " + c
+	}
+	return result + " changed"
+}
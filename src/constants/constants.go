@@ -16,6 +16,25 @@ const (
 	MessageInvalidToken         = "Invalid or expired token"
 	MessageUnauthorized         = "Unauthorized access"
 	MessageInvalidRequest       = "Invalid request format"
+
+	MessageUploadSessionNotFound = "Upload session not found or expired"
+	MessageInvalidContentRange   = "Invalid or out-of-order Content-Range"
+	MessageDigestMismatch        = "Uploaded content does not match the provided digest"
+	MessageInvalidChunkIndex     = "Invalid chunk index"
+	MessageChunkTooLarge         = "Chunk exceeds the configured maximum chunk size"
+	MessageUploadIncomplete      = "Not all chunks have been received"
+	MessageTooManyInflightChunks = "Too many chunk uploads are already in flight for this session"
+
+	MessageShareNotFound     = "Share not found or expired"
+	MessageShareExpired      = "Share link has expired"
+	MessageShareExhausted    = "Share link has reached its download limit"
+	MessageSharePasswordBad  = "Incorrect share password"
+	MessageShareDeleteKeyBad = "Incorrect delete key"
+
+	MessageFolderNotFound           = "Folder not found"
+	MessageInvalidParent            = "Parent is not a folder"
+	MessageCannotCopyDir            = "Cannot copy a folder"
+	MessageCannotMoveIntoDescendant = "Cannot move a folder into itself or one of its own subfolders"
 )
 
 // Default values
@@ -30,6 +49,7 @@ const (
 const (
 	AuthorizationHeader = "Authorization"
 	BearerPrefix        = "Bearer "
+	AuthCookieName      = "auth"
 )
 
 // Database related constants
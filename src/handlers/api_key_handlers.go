@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"file-storage-api/src/auth"
+	"file-storage-api/src/models"
+)
+
+// APIKeyHandler handles minting and revoking long-lived API keys
+type APIKeyHandler struct{}
+
+// CreateAPIKey mints a new API key for the caller
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request format"})
+		return
+	}
+
+	plaintext, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		logServerError(c, "failed to generate API key", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create API key"})
+		return
+	}
+
+	key := &models.APIKey{
+		UserID:  userIDObj,
+		Name:    req.Name,
+		KeyHash: hash,
+	}
+	if err := models.CreateAPIKey(c.Request.Context(), key); err != nil {
+		logServerError(c, "failed to save API key", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create API key"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"id":  key.ID.Hex(),
+		"key": plaintext,
+	})
+}
+
+// ListAPIKeys lists the caller's API keys (without their secret values)
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	keys, err := models.GetAPIKeysByUser(c.Request.Context(), userIDObj)
+	if err != nil {
+		logServerError(c, "failed to list API keys", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list API keys"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
+
+// RevokeAPIKey revokes one of the caller's API keys by ID
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	keyID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid API key ID"})
+		return
+	}
+
+	if err := models.RevokeAPIKeyForUser(c.Request.Context(), keyID, userIDObj); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "API key not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
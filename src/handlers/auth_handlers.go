@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/yourusername/file-storage-api/src/auth"
+	"github.com/yourusername/file-storage-api/src/config"
 	"github.com/yourusername/file-storage-api/src/constants"
 	"github.com/yourusername/file-storage-api/src/models"
 )
@@ -25,6 +29,7 @@ func (h *AuthHandler) RegisterUser(c *gin.Context) {
 	// Hash the password
 	hashedPassword, err := auth.HashPassword(req.Password)
 	if err != nil {
+		logServerError(c, "failed to hash password", err)
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to process password"})
 		return
 	}
@@ -40,7 +45,7 @@ func (h *AuthHandler) RegisterUser(c *gin.Context) {
 		StorageUsed:  0,
 	}
 
-	if err := models.CreateUser(user); err != nil {
+	if err := models.CreateUser(c.Request.Context(), user); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
 		return
 	}
@@ -67,7 +72,7 @@ func (h *AuthHandler) LoginUser(c *gin.Context) {
 	}
 
 	// Find user by username
-	user, err := models.GetUserByUsername(req.Username)
+	user, err := models.GetUserByUsername(c.Request.Context(), req.Username)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: constants.MessageInvalidCredentials})
 		return
@@ -79,15 +84,17 @@ func (h *AuthHandler) LoginUser(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := auth.GenerateToken(*user)
+	// Issue a short-lived access token plus a long-lived refresh token
+	accessToken, refreshToken, err := issueTokenPair(c, *user)
 	if err != nil {
+		logServerError(c, "failed to issue token pair", err)
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate authentication token"})
 		return
 	}
 
 	c.JSON(http.StatusOK, models.AuthResponse{
-		Token: token,
+		Token:        accessToken,
+		RefreshToken: refreshToken,
 		User: models.User{
 			ID:           user.ID,
 			Username:     user.Username,
@@ -99,42 +106,158 @@ func (h *AuthHandler) LoginUser(c *gin.Context) {
 	})
 }
 
-// AuthMiddleware is a middleware to check if the user is authenticated
-func AuthMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Get the token from the Authorization header
-		authHeader := c.GetHeader(constants.AuthorizationHeader)
-		if authHeader == "" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Authorization header is required"})
-			return
-		}
+// issueTokenPair generates a new access/refresh token pair for user,
+// persists the refresh token (hashed) against the requesting device, and
+// sets the access token as an httpOnly cookie for browser clients.
+func issueTokenPair(c *gin.Context, user models.User) (accessToken, refreshToken string, err error) {
+	accessToken, err = auth.GenerateAccessToken(user)
+	if err != nil {
+		return "", "", err
+	}
 
-		// Expecting "Bearer <token>"
-		if len(authHeader) <= len(constants.BearerPrefix) || authHeader[:len(constants.BearerPrefix)] != constants.BearerPrefix {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Authorization header format must be Bearer <token>"})
-			return
-		}
-		tokenString := authHeader[len(constants.BearerPrefix):]
+	plaintext, hash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return "", "", err
+	}
 
-		// Validate the token
-		userID, err := auth.ValidateToken(tokenString)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: constants.MessageInvalidToken})
-			return
+	rt := &models.RefreshToken{
+		TokenHash: hash,
+		UserID:    user.ID,
+		ExpiresAt: time.Now().Add(config.GetRefreshTokenTTL()),
+		UserAgent: c.Request.UserAgent(),
+		IP:        c.ClientIP(),
+	}
+	if err := models.CreateRefreshToken(c.Request.Context(), rt); err != nil {
+		return "", "", err
+	}
+
+	c.SetCookie(constants.AuthCookieName, accessToken, int(config.GetAccessTokenTTL().Seconds()), "/", "", false, true)
+
+	return accessToken, plaintext, nil
+}
+
+// RefreshToken rotates a refresh token, issuing a new access/refresh pair
+// and revoking the old refresh token so a replayed old token is detected
+func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	_ = c.ShouldBindJSON(&req)
+	if req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageInvalidRequest})
+		return
+	}
+
+	rt, err := models.GetRefreshTokenByHash(c.Request.Context(), auth.HashRefreshToken(req.RefreshToken))
+	if err != nil || rt.Revoked || time.Now().After(rt.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: constants.MessageInvalidToken})
+		return
+	}
+
+	user, err := models.GetUserByID(c.Request.Context(), rt.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: constants.MessageUserNotFound})
+		return
+	}
+
+	// Rotate: revoke the presented token before issuing a new one, so a
+	// replayed old token can be detected as already-revoked.
+	if err := models.RevokeRefreshToken(c.Request.Context(), rt.ID); err != nil {
+		logServerError(c, "failed to revoke refresh token", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to rotate refresh token"})
+		return
+	}
+
+	accessToken, refreshToken, err := issueTokenPair(c, *user)
+	if err != nil {
+		logServerError(c, "failed to issue token pair", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate authentication token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.AuthResponse{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		User:         *user,
+	})
+}
+
+// Logout revokes the caller's refresh token and clears the auth cookie
+func (h *AuthHandler) Logout(c *gin.Context) {
+	var req struct {
+		RefreshToken string `json:"refresh_token"`
+	}
+	_ = c.ShouldBindJSON(&req)
+
+	if req.RefreshToken != "" {
+		if rt, err := models.GetRefreshTokenByHash(c.Request.Context(), auth.HashRefreshToken(req.RefreshToken)); err == nil {
+			_ = models.RevokeRefreshToken(c.Request.Context(), rt.ID)
 		}
+	}
+
+	c.SetCookie(constants.AuthCookieName, "", -1, "/", "", false, true)
+	c.Status(http.StatusNoContent)
+}
+
+// GetSessions lists the caller's active and past login sessions (one per
+// refresh token/device)
+func (h *AuthHandler) GetSessions(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	sessions, err := models.GetRefreshTokensByUser(c.Request.Context(), userIDObj)
+	if err != nil {
+		logServerError(c, "failed to list sessions", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list sessions"})
+		return
+	}
 
-		// Get user from store
-		user, err := models.GetUserByID(userID)
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: constants.MessageUserNotFound})
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+// DeleteSession revokes a single session by its refresh token ID
+func (h *AuthHandler) DeleteSession(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	sessionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid session ID"})
+		return
+	}
+
+	if err := models.RevokeRefreshTokenForUser(c.Request.Context(), sessionID, userIDObj); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageInvalidToken})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AuthMiddleware authenticates each request against the given Authers in
+// order, accepting the first one that succeeds. This lets the same routes
+// be reached with a JWT, a session cookie, or an API key, depending on how
+// config.yml's auth.providers is set.
+func AuthMiddleware(authers ...auth.Auther) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var lastErr error
+		for _, a := range authers {
+			user, err := a.Authenticate(c.Request)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			c.Set("userID", user.ID)
+			c.Set("user", user)
+			c.Next()
 			return
 		}
 
-		// Set the user ID and user in the context for future use
-		c.Set("userID", userID)
-		c.Set("user", user)
-
-		c.Next()
+		if lastErr == nil {
+			lastErr = errors.New(constants.MessageUnauthorized)
+		}
+		c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: lastErr.Error()})
 	}
 }
 
@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"fmt"
+	"io"
 	"math"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -45,7 +50,7 @@ func (h *FileHandler) UploadFile(c *gin.Context) {
 	}
 	defer file.Close()
 	// Upload file
-	metadata, err := h.FileService.UploadFile(userIDObj, header.Filename, file)
+	metadata, err := h.FileService.UploadFile(c.Request.Context(), userIDObj, header.Filename, header.Header.Get("Content-Type"), file)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
 		return
@@ -64,12 +69,14 @@ func (h *FileHandler) UploadFile(c *gin.Context) {
 		// Set the size directly as the formatted float value
 		size := config.FormatStorageSize(metadata.Size)
 		response["file"] = gin.H{
-			"id":         formattedMeta.ID,
-			"user_id":    formattedMeta.UserID,
-			"file_name":  formattedMeta.FileName,
-			"size":       size,
-			"created_at": formattedMeta.CreatedAt,
-			"updated_at": formattedMeta.UpdatedAt,
+			"id":           formattedMeta.ID,
+			"user_id":      formattedMeta.UserID,
+			"file_name":    formattedMeta.FileName,
+			"digest":       formattedMeta.Digest,
+			"content_type": formattedMeta.ContentType,
+			"size":         size,
+			"created_at":   formattedMeta.CreatedAt,
+			"updated_at":   formattedMeta.UpdatedAt,
 		}
 	}
 
@@ -93,8 +100,9 @@ func (h *FileHandler) ListFiles(c *gin.Context) {
 	}
 
 	// Get files with pagination
-	files, totalCount, err := models.GetFilesByUserIDPaginated(userIDObj, page, pageSize)
+	files, totalCount, err := models.GetFilesByUserIDPaginated(c.Request.Context(), userIDObj, page, pageSize)
 	if err != nil {
+		logServerError(c, "failed to list files", err)
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list files"})
 		return
 	}
@@ -123,12 +131,14 @@ func (h *FileHandler) ListFiles(c *gin.Context) {
 			// Use a map to represent the file with properly formatted size
 			size := config.FormatStorageSize(file.Size)
 			formattedFiles[i] = gin.H{
-				"id":         file.ID,
-				"user_id":    file.UserID,
-				"file_name":  file.FileName,
-				"size":       size,
-				"created_at": file.CreatedAt,
-				"updated_at": file.UpdatedAt,
+				"id":           file.ID,
+				"user_id":      file.UserID,
+				"file_name":    file.FileName,
+				"digest":       file.Digest,
+				"content_type": file.ContentType,
+				"size":         size,
+				"created_at":   file.CreatedAt,
+				"updated_at":   file.UpdatedAt,
 			}
 		}
 		response["files"] = formattedFiles
@@ -148,7 +158,7 @@ func (h *FileHandler) GetFile(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid file ID"})
 		return
 	}
-	file, err := models.GetFileByID(fileID, userIDObj)
+	file, err := models.GetFileByID(c.Request.Context(), fileID, userIDObj)
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageFileNotFound})
 		return
@@ -163,18 +173,38 @@ func (h *FileHandler) GetFile(c *gin.Context) {
 		// Format file size using a clean map representation
 		size := config.FormatStorageSize(file.Size)
 		response["file"] = gin.H{
-			"id":         file.ID,
-			"user_id":    file.UserID,
-			"file_name":  file.FileName,
-			"size":       size,
-			"created_at": file.CreatedAt,
-			"updated_at": file.UpdatedAt,
+			"id":           file.ID,
+			"user_id":      file.UserID,
+			"file_name":    file.FileName,
+			"digest":       file.Digest,
+			"content_type": file.ContentType,
+			"size":         size,
+			"created_at":   file.CreatedAt,
+			"updated_at":   file.UpdatedAt,
 		}
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// GetFileByDigest checks whether a blob with the given sha256 digest is
+// already stored, letting clients skip re-uploading content they know the
+// server already has.
+func (h *FileHandler) GetFileByDigest(c *gin.Context) {
+	digest := c.Param("sha256")
+
+	blob, err := models.GetBlobByDigest(c.Request.Context(), digest)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageFileNotFound})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"digest": blob.Digest,
+		"size":   blob.Size,
+	})
+}
+
 // DownloadFile downloads a specific file by ID
 func (h *FileHandler) DownloadFile(c *gin.Context) {
 	userID, _ := c.Get("userID")
@@ -187,7 +217,61 @@ func (h *FileHandler) DownloadFile(c *gin.Context) {
 		return
 	}
 
-	fileName, file, err := h.FileService.DownloadFile(fileID, userIDObj)
+	rangeHeader := c.GetHeader("Range")
+
+	// An If-Range that doesn't match the file's current ETag means the
+	// representation changed since the client cached it, so fall back to
+	// serving the full, fresh content instead of a (now wrong) range.
+	if ifRange := c.GetHeader("If-Range"); rangeHeader != "" && ifRange != "" {
+		metaFile, err := models.GetFileByID(c.Request.Context(), fileID, userIDObj)
+		if err != nil {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageFileNotFound})
+			return
+		}
+		if ifRange != etag(metaFile.Digest) {
+			rangeHeader = ""
+		}
+	}
+
+	if rangeHeader == "" {
+		fileName, digest, _, file, err := h.FileService.DownloadFile(c.Request.Context(), fileID, userIDObj)
+		if err != nil {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageFileNotFound})
+			return
+		}
+		defer file.Close()
+
+		c.Header("Content-Disposition", "attachment; filename="+fileName)
+		c.Header("Content-Type", "application/octet-stream")
+		c.Header("Accept-Ranges", "bytes")
+		if digest != "" {
+			c.Header("ETag", etag(digest))
+		}
+		c.Status(http.StatusOK)
+		_, _ = io.Copy(c.Writer, file)
+		return
+	}
+
+	metaFile, err := models.GetFileByID(c.Request.Context(), fileID, userIDObj)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageFileNotFound})
+		return
+	}
+
+	ranges, err := parseRangeHeader(rangeHeader, metaFile.Size)
+	if err != nil {
+		c.Header("Content-Range", fmt.Sprintf("bytes */%d", metaFile.Size))
+		c.JSON(http.StatusRequestedRangeNotSatisfiable, models.ErrorResponse{Error: "Invalid Range header"})
+		return
+	}
+
+	if len(ranges) > 1 {
+		h.downloadMultiRange(c, fileID, userIDObj, ranges, metaFile.Size)
+		return
+	}
+
+	start, end := ranges[0].start, ranges[0].end
+	fileName, digest, size, file, err := h.FileService.DownloadFileRange(c.Request.Context(), fileID, userIDObj, start, end-start+1)
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageFileNotFound})
 		return
@@ -196,7 +280,146 @@ func (h *FileHandler) DownloadFile(c *gin.Context) {
 
 	c.Header("Content-Disposition", "attachment; filename="+fileName)
 	c.Header("Content-Type", "application/octet-stream")
-	c.File(file.Name())
+	c.Header("Accept-Ranges", "bytes")
+	c.Header("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, size))
+	c.Header("Content-Length", strconv.FormatInt(end-start+1, 10))
+	if digest != "" {
+		c.Header("ETag", etag(digest))
+	}
+	c.Status(http.StatusPartialContent)
+	_, _ = io.Copy(c.Writer, file)
+}
+
+// etag formats a file's content digest as an HTTP entity tag
+func etag(digest string) string {
+	return `"` + digest + `"`
+}
+
+// byteRange is one inclusive [start, end] span of a "bytes=..." Range header.
+type byteRange struct {
+	start, end int64
+}
+
+// parseRangeHeader parses a "bytes=..." request header, which may name one
+// or more comma-separated ranges, against a resource of the given total
+// size. A single range is the common case (the only form most media players
+// and download managers send); more than one is handled by downloadMultiRange,
+// which serves them as a multipart/byteranges response per RFC 7233 §4.1.
+func parseRangeHeader(header string, size int64) ([]byteRange, error) {
+	const prefix = "bytes="
+	if !strings.HasPrefix(header, prefix) {
+		return nil, fmt.Errorf("unsupported Range unit")
+	}
+	spec := strings.TrimPrefix(header, prefix)
+
+	specs := strings.Split(spec, ",")
+	ranges := make([]byteRange, 0, len(specs))
+	for _, s := range specs {
+		start, end, err := parseOneRange(strings.TrimSpace(s), size)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, byteRange{start, end})
+	}
+
+	return ranges, nil
+}
+
+// parseOneRange parses a single "start-end" range spec (with either side
+// optionally omitted) against a resource of the given total size.
+func parseOneRange(spec string, size int64) (start, end int64, err error) {
+	parts := strings.SplitN(spec, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("malformed Range: %q", spec)
+	}
+
+	if parts[0] == "" {
+		// Suffix range: last N bytes
+		suffixLen, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || suffixLen <= 0 {
+			return 0, 0, fmt.Errorf("malformed suffix range: %q", spec)
+		}
+		if suffixLen > size {
+			suffixLen = size
+		}
+		return size - suffixLen, size - 1, nil
+	}
+
+	start, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || start >= size {
+		return 0, 0, fmt.Errorf("range start out of bounds")
+	}
+
+	if parts[1] == "" {
+		return start, size - 1, nil
+	}
+
+	end, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || end < start {
+		return 0, 0, fmt.Errorf("malformed range end: %q", spec)
+	}
+	if end >= size {
+		end = size - 1
+	}
+
+	return start, end, nil
+}
+
+// downloadMultiRange serves a multi-range request as a single
+// multipart/byteranges response, opening and streaming each requested span
+// through the storage driver in turn.
+func (h *FileHandler) downloadMultiRange(c *gin.Context, fileID, userID primitive.ObjectID, ranges []byteRange, totalSize int64) {
+	mw := multipart.NewWriter(c.Writer)
+	defer mw.Close()
+
+	c.Header("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+	c.Header("Accept-Ranges", "bytes")
+	c.Status(http.StatusPartialContent)
+
+	for _, r := range ranges {
+		_, _, _, file, err := h.FileService.DownloadFileRange(c.Request.Context(), fileID, userID, r.start, r.end-r.start+1)
+		if err != nil {
+			return
+		}
+
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":  {"application/octet-stream"},
+			"Content-Range": {fmt.Sprintf("bytes %d-%d/%d", r.start, r.end, totalSize)},
+		})
+		if err != nil {
+			file.Close()
+			return
+		}
+
+		_, _ = io.Copy(part, file)
+		file.Close()
+	}
+}
+
+// VerifyFile recomputes a file's content digest and compares it against the
+// one recorded at upload time, catching corruption or tampering in the
+// storage backend.
+func (h *FileHandler) VerifyFile(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+	fileIDStr := c.Param("id")
+
+	fileID, err := primitive.ObjectIDFromHex(fileIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid file ID"})
+		return
+	}
+
+	ok, digest, err := h.FileService.VerifyFile(c.Request.Context(), fileID, userIDObj)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageFileNotFound})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"valid":  ok,
+		"digest": digest,
+	})
 }
 
 // DeleteFile deletes a specific file by ID
@@ -211,7 +434,7 @@ func (h *FileHandler) DeleteFile(c *gin.Context) {
 		return
 	}
 
-	if err := h.FileService.DeleteFile(fileID, userIDObj); err != nil {
+	if err := h.FileService.DeleteFile(c.Request.Context(), fileID, userIDObj); err != nil {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageFileNotFound})
 		return
 	}
@@ -221,6 +444,82 @@ func (h *FileHandler) DeleteFile(c *gin.Context) {
 	})
 }
 
+// moveOrCopyRequest is the shared body for MoveFile and CopyFile: the
+// destination directory, identified the same way ListChildren's :id param
+// is (empty string or "root" for the storage root).
+type moveOrCopyRequest struct {
+	ParentID string `json:"parent_id"`
+}
+
+// MoveFile relocates a file or directory (and, for a directory, its entire
+// subtree) to a new parent directory.
+func (h *FileHandler) MoveFile(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+	fileIDStr := c.Param("id")
+
+	fileID, err := primitive.ObjectIDFromHex(fileIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid file ID"})
+		return
+	}
+
+	var req moveOrCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageInvalidRequest})
+		return
+	}
+
+	newParentID, err := parseFolderID(req.ParentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid parent_id"})
+		return
+	}
+
+	if err := models.MoveFile(c.Request.Context(), fileID, userIDObj, newParentID); err != nil {
+		logServerError(c, "failed to move file", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "File moved successfully"})
+}
+
+// CopyFile duplicates a file into a new parent directory. Directories
+// themselves cannot be copied.
+func (h *FileHandler) CopyFile(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+	fileIDStr := c.Param("id")
+
+	fileID, err := primitive.ObjectIDFromHex(fileIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid file ID"})
+		return
+	}
+
+	var req moveOrCopyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageInvalidRequest})
+		return
+	}
+
+	newParentID, err := parseFolderID(req.ParentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid parent_id"})
+		return
+	}
+
+	copied, err := models.CopyFile(c.Request.Context(), fileID, userIDObj, newParentID)
+	if err != nil {
+		logServerError(c, "failed to copy file", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"file": copied})
+}
+
 // GetRemainingStorage gets the remaining storage space for the current user
 func (h *FileHandler) GetRemainingStorage(c *gin.Context) {
 	userObj, _ := c.Get("user")
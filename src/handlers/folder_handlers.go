@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"file-storage-api/src/constants"
+	"file-storage-api/src/models"
+)
+
+// FolderHandler handles directory operations: creating folders and listing
+// their contents. Moving/copying a folder reuses FileHandler.MoveFile and
+// FileHandler.CopyFile, since a directory is just a FileMetadata row with
+// IsDir set.
+type FolderHandler struct{}
+
+// parseFolderID resolves a folder ID path param to an ObjectID. Both "" and
+// "root" mean the storage root, which is represented internally by
+// primitive.NilObjectID rather than a real document.
+func parseFolderID(raw string) (primitive.ObjectID, error) {
+	if raw == "" || raw == "root" {
+		return primitive.NilObjectID, nil
+	}
+	return primitive.ObjectIDFromHex(raw)
+}
+
+// createFolderRequest is the body for CreateFolder
+type createFolderRequest struct {
+	Name     string `json:"name" binding:"required"`
+	ParentID string `json:"parent_id"`
+}
+
+// CreateFolder creates a new, empty directory under the given parent
+func (h *FolderHandler) CreateFolder(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	var req createFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageInvalidRequest})
+		return
+	}
+
+	parentID, err := parseFolderID(req.ParentID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid parent_id"})
+		return
+	}
+
+	dir, err := models.CreateDirectory(c.Request.Context(), userIDObj, req.Name, parentID)
+	if err != nil {
+		logServerError(c, "failed to create folder", err)
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"folder": dir})
+}
+
+// ListChildren lists the immediate children of a folder, paginated the same
+// way FileHandler.ListFiles is.
+func (h *FolderHandler) ListChildren(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	parentID, err := parseFolderID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid folder ID"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(c.DefaultQuery("page_size", "10"))
+	if err != nil || pageSize < 1 || pageSize > 100 {
+		pageSize = 10
+	}
+
+	children, totalCount, err := models.ListByParent(c.Request.Context(), userIDObj, parentID, page, pageSize)
+	if err != nil {
+		logServerError(c, "failed to list folder children", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list folder contents"})
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(totalCount) / float64(pageSize)))
+	c.JSON(http.StatusOK, gin.H{
+		"children": children,
+		"pagination": gin.H{
+			"total_items":  totalCount,
+			"total_pages":  totalPages,
+			"current_page": page,
+			"page_size":    pageSize,
+			"has_next":     page < totalPages,
+			"has_prev":     page > 1,
+		},
+	})
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"file-storage-api/src/models"
+)
+
+// RateLimitByIP returns a middleware that allows at most maxRequests per
+// client IP within window, to deter brute-force enumeration of share
+// tokens.
+func RateLimitByIP(maxRequests int, window time.Duration) gin.HandlerFunc {
+	var mu sync.Mutex
+	hits := map[string][]time.Time{}
+
+	return func(c *gin.Context) {
+		ip := c.ClientIP()
+		now := time.Now()
+
+		mu.Lock()
+		recent := hits[ip][:0]
+		for _, t := range hits[ip] {
+			if now.Sub(t) < window {
+				recent = append(recent, t)
+			}
+		}
+		allowed := len(recent) < maxRequests
+		if allowed {
+			recent = append(recent, now)
+		}
+		hits[ip] = recent
+		mu.Unlock()
+
+		if !allowed {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, models.ErrorResponse{Error: "Too many requests"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"file-storage-api/src/logging"
+)
+
+// RequestIDHeader is the header clients may set to propagate a request ID
+// through to our logs (e.g. from an upstream proxy); one is generated when
+// absent.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the gin.Context key RequestID() stores the ID under.
+const requestIDKey = "requestID"
+
+// RequestID generates or accepts an X-Request-ID, stores it on the gin
+// context, and echoes it back on the response so callers can correlate
+// their request with our logs.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" {
+			id = primitive.NewObjectID().Hex()
+		}
+
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// logServerError logs a handler-level failure (usually a 500 response; the
+// folder create/move/copy handlers also call it before their 400 responses)
+// with the request ID and user ID for correlation, alongside the underlying
+// error.
+func logServerError(c *gin.Context, msg string, err error) {
+	attrs := []any{"error", err}
+	if id, ok := c.Get(requestIDKey); ok {
+		attrs = append(attrs, "request_id", id)
+	}
+	if userID, ok := c.Get("userID"); ok {
+		attrs = append(attrs, "user_id", userID)
+	}
+	logging.Logger.Error(msg, attrs...)
+}
+
+// RequestLogger emits one structured access log line per request, via
+// logging.Logger, with fields operators need to correlate and debug
+// requests: method, path, status, latency, request ID, and user ID (once
+// AuthMiddleware has populated it).
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"latency", time.Since(start).String(),
+			"client_ip", c.ClientIP(),
+		}
+
+		if id, ok := c.Get(requestIDKey); ok {
+			attrs = append(attrs, "request_id", id)
+		}
+
+		if userID, ok := c.Get("userID"); ok {
+			attrs = append(attrs, "user_id", userID)
+		}
+
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "error", c.Errors.String())
+			logging.Logger.Error("request", attrs...)
+			return
+		}
+
+		logging.Logger.Info("request", attrs...)
+	}
+}
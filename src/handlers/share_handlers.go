@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"file-storage-api/src/constants"
+	"file-storage-api/src/models"
+	"file-storage-api/src/storage"
+)
+
+// ShareHandler handles creating and resolving public share links
+type ShareHandler struct {
+	FileService *storage.FileService
+}
+
+// NewShareHandler creates a new ShareHandler
+func NewShareHandler(fileService *storage.FileService) *ShareHandler {
+	return &ShareHandler{FileService: fileService}
+}
+
+type createShareRequest struct {
+	ExpiresInSeconds int64  `json:"expires_in_seconds" binding:"required"`
+	MaxDownloads     int    `json:"max_downloads" binding:"required"`
+	Password         string `json:"password"`
+}
+
+// CreateShare creates a time-limited public link to download a file the
+// caller owns
+func (h *ShareHandler) CreateShare(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	fileID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid file ID"})
+		return
+	}
+
+	// Ownership check
+	if _, err := models.GetFileByID(c.Request.Context(), fileID, userIDObj); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageFileNotFound})
+		return
+	}
+
+	var req createShareRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageInvalidRequest})
+		return
+	}
+
+	expiresAt := time.Now().Add(time.Duration(req.ExpiresInSeconds) * time.Second)
+	token, deleteKey, err := models.CreateShare(c.Request.Context(), fileID, userIDObj, expiresAt, req.MaxDownloads, req.Password)
+	if err != nil {
+		logServerError(c, "failed to create share", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create share"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"share_url":  "/s/" + token,
+		"delete_key": deleteKey,
+	})
+}
+
+// DownloadShare streams the shared file to an unauthenticated caller,
+// enforcing expiry, download limits, and an optional password.
+func (h *ShareHandler) DownloadShare(c *gin.Context) {
+	file, _, err := models.ResolveShare(c.Request.Context(), c.Param("token"), c.Query("password"))
+	if err != nil {
+		c.JSON(shareErrorStatus(err), models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	rc, err := h.FileService.OpenByKey(c.Request.Context(), file.StorageKey)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageFileNotFound})
+		return
+	}
+	defer rc.Close()
+
+	c.Header("Content-Disposition", "attachment; filename="+file.FileName)
+	c.Header("Content-Type", "application/octet-stream")
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, rc)
+}
+
+// RevokeShare deletes a share once its delete key is presented
+func (h *ShareHandler) RevokeShare(c *gin.Context) {
+	token := c.Param("token")
+
+	if err := models.RevokeShare(c.Request.Context(), token, c.Query("key")); err != nil {
+		status := http.StatusNotFound
+		if err.Error() == constants.MessageShareDeleteKeyBad {
+			status = http.StatusUnauthorized
+		}
+		c.JSON(status, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// shareErrorStatus maps the sentinel errors models.ResolveShare returns to
+// the HTTP status a public share download should fail with.
+func shareErrorStatus(err error) int {
+	switch err.Error() {
+	case constants.MessageShareExpired, constants.MessageShareExhausted:
+		return http.StatusGone
+	case constants.MessageSharePasswordBad:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusNotFound
+	}
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+
+	"file-storage-api/src/config"
+)
+
+// RequestTimeout bounds how long a single request may run by installing a
+// context.WithTimeout-derived context on c.Request before calling c.Next(),
+// so slow Mongo queries or storage driver calls are cancelled instead of
+// piling up indefinitely.
+func RequestTimeout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), config.GetRequestTimeout())
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
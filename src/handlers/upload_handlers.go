@@ -0,0 +1,456 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"file-storage-api/src/config"
+	"file-storage-api/src/constants"
+	"file-storage-api/src/models"
+	"file-storage-api/src/storage"
+)
+
+// uploadSessionTTL bounds how long an abandoned upload session's staging
+// file is kept around before the client must start over.
+const uploadSessionTTL = 24 * time.Hour
+
+// UploadHandler handles resumable, chunked file uploads
+type UploadHandler struct {
+	FileService *storage.FileService
+	stagingDir  string
+
+	// inflightMu guards inflightChunks, which bounds how many concurrent
+	// chunk uploads (UploadChunk) a single session may have in flight, one
+	// semaphore channel per session.
+	inflightMu     sync.Mutex
+	inflightChunks map[primitive.ObjectID]chan struct{}
+}
+
+// NewUploadHandler creates a new UploadHandler, staging chunks under the OS
+// temp directory until an upload is finalized into the storage driver.
+func NewUploadHandler(fileService *storage.FileService) *UploadHandler {
+	stagingDir := filepath.Join(os.TempDir(), "file-storage-api-uploads")
+	_ = os.MkdirAll(stagingDir, 0755)
+
+	return &UploadHandler{
+		FileService:    fileService,
+		stagingDir:     stagingDir,
+		inflightChunks: make(map[primitive.ObjectID]chan struct{}),
+	}
+}
+
+// CreateUploadSession creates a new resumable upload session
+func (h *UploadHandler) CreateUploadSession(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	var req struct {
+		FileName  string `json:"file_name" binding:"required"`
+		TotalSize int64  `json:"total_size"`
+		ChunkSize int64  `json:"chunk_size"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageInvalidRequest})
+		return
+	}
+
+	if req.ChunkSize > config.GetMaxChunkSize() {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageChunkTooLarge})
+		return
+	}
+
+	session := &models.UploadSession{
+		UserID:      userIDObj,
+		FileName:    req.FileName,
+		StagingPath: filepath.Join(h.stagingDir, primitive.NewObjectID().Hex()+".part"),
+		ExpiresAt:   time.Now().Add(uploadSessionTTL),
+	}
+
+	// A non-zero ChunkSize opts into the indexed-chunk upload flow
+	// (UploadChunk/CompleteUpload) instead of the byte-offset flow
+	// (AppendChunk/FinalizeUpload); pre-size the bitmap tracking which
+	// chunks have arrived.
+	if req.ChunkSize > 0 {
+		session.TotalSize = req.TotalSize
+		session.ChunkSize = req.ChunkSize
+		session.ReceivedBitmap = make([]bool, session.NumChunks())
+	}
+
+	stagingFile, err := os.Create(session.StagingPath)
+	if err != nil {
+		logServerError(c, "failed to create upload staging file", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create upload session"})
+		return
+	}
+	stagingFile.Close()
+
+	if err := models.CreateUploadSession(c.Request.Context(), session); err != nil {
+		_ = os.Remove(session.StagingPath)
+		logServerError(c, "failed to create upload session", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create upload session"})
+		return
+	}
+
+	location := "/api/files/uploads/" + session.ID.Hex()
+	c.Header("Location", location)
+	c.Header("Upload-Session-UUID", session.ID.Hex())
+	response := gin.H{
+		"id":     session.ID.Hex(),
+		"offset": session.Offset,
+	}
+	if session.ChunkSize > 0 {
+		response["chunk_size"] = session.ChunkSize
+		response["total_chunks"] = session.NumChunks()
+	}
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetUploadStatus returns the current offset of an upload session
+func (h *UploadHandler) GetUploadStatus(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	session, err := h.lookupSession(c, userIDObj)
+	if err != nil {
+		return
+	}
+
+	c.Header("Range", fmt.Sprintf("0-%d", session.Offset-1))
+	c.JSON(http.StatusOK, gin.H{
+		"id":     session.ID.Hex(),
+		"offset": session.Offset,
+	})
+}
+
+// AppendChunk appends a byte range to an in-progress upload session
+func (h *UploadHandler) AppendChunk(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	session, err := h.lookupSession(c, userIDObj)
+	if err != nil {
+		return
+	}
+
+	start, end, err := parseContentRange(c.GetHeader("Content-Range"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageInvalidContentRange})
+		return
+	}
+
+	if start != session.Offset {
+		c.JSON(http.StatusRequestedRangeNotSatisfiable, models.ErrorResponse{Error: constants.MessageInvalidContentRange})
+		return
+	}
+
+	userObj, _ := c.Get("user")
+	user := userObj.(*models.User)
+	if end+1 > user.StorageLimit-user.StorageUsed {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageStorageLimitExceeded})
+		return
+	}
+
+	stagingFile, err := os.OpenFile(session.StagingPath, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		logServerError(c, "failed to open upload staging file", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to open upload session"})
+		return
+	}
+	defer stagingFile.Close()
+
+	written, err := io.Copy(stagingFile, c.Request.Body)
+	if err != nil {
+		logServerError(c, "failed to write upload chunk", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to write chunk"})
+		return
+	}
+
+	newOffset := start + written
+	if err := models.UpdateUploadSessionOffset(c.Request.Context(), session.ID, newOffset); err != nil {
+		logServerError(c, "failed to update upload session offset", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update upload session"})
+		return
+	}
+
+	c.Header("Range", fmt.Sprintf("0-%d", newOffset-1))
+	c.Header("Location", "/api/files/uploads/"+session.ID.Hex())
+	c.Status(http.StatusNoContent)
+}
+
+// acquireChunkSlot bounds how many UploadChunk requests for a single
+// session run concurrently, to keep a client from overwhelming the server
+// (or its own staging file) by firing every chunk at once. The returned
+// release func must be called once the chunk write is done; ok is false if
+// the session is already at MaxInflightChunks.
+func (h *UploadHandler) acquireChunkSlot(sessionID primitive.ObjectID) (release func(), ok bool) {
+	h.inflightMu.Lock()
+	sem, exists := h.inflightChunks[sessionID]
+	if !exists {
+		sem = make(chan struct{}, config.GetMaxInflightChunks())
+		h.inflightChunks[sessionID] = sem
+	}
+	h.inflightMu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, true
+	default:
+		return nil, false
+	}
+}
+
+// forgetChunkSlots drops a finished session's inflight semaphore so it
+// doesn't linger in memory.
+func (h *UploadHandler) forgetChunkSlots(sessionID primitive.ObjectID) {
+	h.inflightMu.Lock()
+	delete(h.inflightChunks, sessionID)
+	h.inflightMu.Unlock()
+}
+
+// UploadChunk writes one fixed-size, independently-addressed chunk of an
+// indexed-chunk upload session (one created with a non-zero chunk_size).
+// Chunks may arrive out of order and concurrently, bounded by
+// config.GetMaxInflightChunks() per session.
+func (h *UploadHandler) UploadChunk(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	session, err := h.lookupSession(c, userIDObj)
+	if err != nil {
+		return
+	}
+
+	if session.ChunkSize <= 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageInvalidChunkIndex})
+		return
+	}
+
+	index, err := strconv.ParseInt(c.Param("index"), 10, 64)
+	if err != nil || index < 0 || index >= session.NumChunks() {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageInvalidChunkIndex})
+		return
+	}
+
+	release, ok := h.acquireChunkSlot(session.ID)
+	if !ok {
+		c.JSON(http.StatusTooManyRequests, models.ErrorResponse{Error: constants.MessageTooManyInflightChunks})
+		return
+	}
+	defer release()
+
+	stagingFile, err := os.OpenFile(session.StagingPath, os.O_WRONLY, 0644)
+	if err != nil {
+		logServerError(c, "failed to open upload staging file", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to open upload session"})
+		return
+	}
+	defer stagingFile.Close()
+
+	offset := index * session.ChunkSize
+	want := session.ChunkSize
+	if last := session.TotalSize - offset; last < want {
+		want = last
+	}
+
+	if _, err := stagingFile.Seek(offset, io.SeekStart); err != nil {
+		logServerError(c, "failed to seek upload staging file", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to write chunk"})
+		return
+	}
+
+	if _, err := io.Copy(stagingFile, io.LimitReader(c.Request.Body, want)); err != nil {
+		logServerError(c, "failed to write upload chunk", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to write chunk"})
+		return
+	}
+
+	if err := models.MarkChunkReceived(c.Request.Context(), session.ID, index); err != nil {
+		logServerError(c, "failed to record received chunk", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update upload session"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CompleteUpload finalizes an indexed-chunk upload session once every chunk
+// has been received, concatenating the staged bytes into the storage
+// backend via FileService.UploadFile.
+func (h *UploadHandler) CompleteUpload(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	session, err := h.lookupSession(c, userIDObj)
+	if err != nil {
+		return
+	}
+
+	if session.ChunkSize <= 0 || !session.AllChunksReceived() {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageUploadIncomplete})
+		return
+	}
+
+	stagingFile, err := os.Open(session.StagingPath)
+	if err != nil {
+		logServerError(c, "failed to read upload staging file", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to read upload session"})
+		return
+	}
+	defer stagingFile.Close()
+
+	metadata, err := h.FileService.UploadFile(c.Request.Context(), userIDObj, session.FileName, "", stagingFile)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	_ = os.Remove(session.StagingPath)
+	_ = models.DeleteUploadSession(c.Request.Context(), session.ID, userIDObj)
+	h.forgetChunkSlots(session.ID)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": constants.MessageFileUploaded,
+		"file":    metadata,
+	})
+}
+
+// FinalizeUpload verifies the digest of a completed upload when one is given
+// as ?digest=sha256:<hex> (without it, no digest check is made), moves it
+// into the storage driver, and creates the resulting file metadata
+func (h *UploadHandler) FinalizeUpload(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	session, err := h.lookupSession(c, userIDObj)
+	if err != nil {
+		return
+	}
+
+	if digest := c.Query("digest"); digest != "" {
+		if err := verifyDigest(session.StagingPath, digest); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: constants.MessageDigestMismatch})
+			return
+		}
+	}
+
+	stagingFile, err := os.Open(session.StagingPath)
+	if err != nil {
+		logServerError(c, "failed to read upload staging file", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to read upload session"})
+		return
+	}
+	defer stagingFile.Close()
+
+	metadata, err := h.FileService.UploadFile(c.Request.Context(), userIDObj, session.FileName, "", stagingFile)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	_ = os.Remove(session.StagingPath)
+	_ = models.DeleteUploadSession(c.Request.Context(), session.ID, userIDObj)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": constants.MessageFileUploaded,
+		"file":    metadata,
+	})
+}
+
+// AbortUpload discards an in-progress upload session
+func (h *UploadHandler) AbortUpload(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	userIDObj := userID.(primitive.ObjectID)
+
+	session, err := h.lookupSession(c, userIDObj)
+	if err != nil {
+		return
+	}
+
+	_ = os.Remove(session.StagingPath)
+	if err := models.DeleteUploadSession(c.Request.Context(), session.ID, userIDObj); err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageUploadSessionNotFound})
+		return
+	}
+	h.forgetChunkSlots(session.ID)
+
+	c.Status(http.StatusNoContent)
+}
+
+// lookupSession resolves the session named by the :id URL param, writing an
+// error response and returning a non-nil error if it cannot be found
+func (h *UploadHandler) lookupSession(c *gin.Context, userID primitive.ObjectID) (*models.UploadSession, error) {
+	sessionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid upload session ID"})
+		return nil, err
+	}
+
+	session, err := models.GetUploadSession(c.Request.Context(), sessionID, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: constants.MessageUploadSessionNotFound})
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// parseContentRange parses a "start-end" Content-Range value (without the
+// "bytes " unit prefix or total size suffix Docker's registry API omits).
+func parseContentRange(header string) (start, end int64, err error) {
+	header = strings.TrimPrefix(header, "bytes ")
+	parts := strings.SplitN(header, "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("malformed Content-Range: %q", header)
+	}
+
+	start, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	end, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if end < start {
+		return 0, 0, fmt.Errorf("invalid range %d-%d", start, end)
+	}
+
+	return start, end, nil
+}
+
+// verifyDigest recomputes the sha256 of the staged file and compares it
+// against a "sha256:<hex>" digest string
+func verifyDigest(path, digest string) error {
+	expected := strings.TrimPrefix(digest, "sha256:")
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(hasher.Sum(nil))
+	if actual != expected {
+		return fmt.Errorf("digest mismatch: expected %s, got %s", expected, actual)
+	}
+	return nil
+}
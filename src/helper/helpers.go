@@ -25,26 +25,41 @@ func SetupTestDatabase(t *testing.T) {
 	// Use a separate test database
 	testConfig := config.Config{
 		Server: struct {
-			Port int    `yaml:"port"`
-			Host string `yaml:"host"`
+			Port                  int    `yaml:"port"`
+			Host                  string `yaml:"host"`
+			RequestTimeoutSeconds int    `yaml:"request_timeout_seconds"`
 		}{
-			Port: 8080,
-			Host: "localhost",
+			Port:                  8080,
+			Host:                  "localhost",
+			RequestTimeoutSeconds: 30,
 		}, JWT: struct {
-			Secret          string `yaml:"secret"`
-			ExpirationHours int    `yaml:"expiration_hours"`
+			Secret             string `yaml:"secret"`
+			ExpirationHours    int    `yaml:"expiration_hours"`
+			AccessTokenMinutes int    `yaml:"access_token_minutes"`
+			RefreshTokenDays   int    `yaml:"refresh_token_days"`
 		}{
-			Secret:          "test-secret-key",
-			ExpirationHours: 24,
+			Secret:             "test-secret-key",
+			ExpirationHours:    24,
+			AccessTokenMinutes: 15,
+			RefreshTokenDays:   30,
 		},
 		Storage: struct {
-			Path        string `yaml:"path"`
-			MaxPerUser  int64  `yaml:"max_per_user"`
-			DisplayInMB bool   `yaml:"display_in_mb"`
+			Path         string                 `yaml:"path"`
+			Driver       string                 `yaml:"driver"`
+			DriverConfig map[string]interface{} `yaml:"driver_config"`
+			MaxPerUser   int64                  `yaml:"max_per_user"`
+			DisplayInMB  bool                   `yaml:"display_in_mb"`
 		}{
-			Path:        "./test-storage",
-			MaxPerUser:  1048576, // 1MB
-			DisplayInMB: false,   // Use bytes for tests
+			Path:         "./test-storage",
+			Driver:       "localfs",
+			DriverConfig: map[string]interface{}{"path": "./test-storage"},
+			MaxPerUser:   1048576, // 1MB
+			DisplayInMB:  false,   // Use bytes for tests
+		},
+		Auth: struct {
+			Providers []string `yaml:"providers"`
+		}{
+			Providers: []string{"jwt", "cookie"},
 		},
 		MongoDB: struct {
 			URI         string `yaml:"uri"`
@@ -64,6 +79,15 @@ func SetupTestDatabase(t *testing.T) {
 				Files: "files",
 			},
 		},
+		Logging: struct {
+			Level  string `yaml:"level"`
+			Format string `yaml:"format"`
+			Output string `yaml:"output"`
+		}{
+			Level:  "error",
+			Format: "text",
+			Output: "stderr",
+		},
 	}
 
 	config.AppConfig = testConfig
@@ -100,6 +124,11 @@ func SetupTestDatabase(t *testing.T) {
 		t.Fatalf("Failed to initialize MongoDB: %v", err)
 	}
 	models.InitFileCollection()
+	models.InitBlobCollection()
+	models.InitShareCollection()
+	models.InitUploadSessionCollection()
+	models.InitRefreshTokenCollection()
+	models.InitAPIKeyCollection()
 }
 
 // CleanupTestDatabase cleans up the test database and storage
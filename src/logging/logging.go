@@ -0,0 +1,63 @@
+// Package logging provides the application's package-level structured
+// logger, configured from config.Logging at startup.
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"os"
+)
+
+// Logger is the process-wide structured logger. It defaults to a
+// text-format logger writing to stderr so logging works before Init runs
+// (e.g. early startup errors), and is replaced once config is loaded.
+var Logger = slog.Default()
+
+// Init builds the process-wide logger from the given level ("debug",
+// "info", "warn", "error"), format ("json" or "text"), and output
+// destination ("stdout", "stderr", or a file path), and installs it as
+// both Logger and the slog default so third-party code using slog's
+// package-level functions picks it up too.
+func Init(level, format, output string) error {
+	w, err := resolveOutput(output)
+	if err != nil {
+		return err
+	}
+
+	opts := &slog.HandlerOptions{Level: resolveLevel(level)}
+
+	var handler slog.Handler
+	if format == "json" {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+
+	Logger = slog.New(handler)
+	slog.SetDefault(Logger)
+	return nil
+}
+
+func resolveLevel(level string) slog.Level {
+	switch level {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func resolveOutput(output string) (io.Writer, error) {
+	switch output {
+	case "", "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	default:
+		return os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	}
+}
@@ -0,0 +1,130 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/file-storage-api/src/config"
+	"github.com/yourusername/file-storage-api/src/constants"
+)
+
+// APIKey represents a long-lived credential a user can mint to authenticate
+// without a password, e.g. for scripts and CI. Only a hash of the key is
+// stored, following the same pattern as RefreshToken.
+type APIKey struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Name      string             `bson:"name" json:"name"`
+	KeyHash   string             `bson:"key_hash" json:"-"`
+	Revoked   bool               `bson:"revoked" json:"revoked"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	LastUsed  time.Time          `bson:"last_used,omitempty" json:"last_used,omitempty"`
+}
+
+var apiKeyCollection *mongo.Collection
+
+// InitAPIKeyCollection initializes the api_keys collection in MongoDB
+func InitAPIKeyCollection() {
+	if client != nil {
+		apiKeyCollection = client.Database(config.GetMongoDBName()).Collection("api_keys")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		indexModel := mongo.IndexModel{
+			Keys:    bson.D{{Key: "key_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		}
+
+		_, _ = apiKeyCollection.Indexes().CreateOne(ctx, indexModel)
+	}
+}
+
+// CreateAPIKey persists a new API key
+func CreateAPIKey(ctx context.Context, key *APIKey) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	key.CreatedAt = time.Now()
+
+	result, err := apiKeyCollection.InsertOne(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	key.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetAPIKeyByHash looks up an API key by the hash of its plaintext value.
+// Revoked keys are returned too; callers check Revoked themselves.
+func GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var key APIKey
+	err := apiKeyCollection.FindOne(ctx, bson.M{"key_hash": keyHash}).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New(constants.MessageInvalidToken)
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// TouchAPIKey records that an API key was just used to authenticate a request
+func TouchAPIKey(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := apiKeyCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"last_used": time.Now()}},
+	)
+	return err
+}
+
+// GetAPIKeysByUser lists every API key (revoked or not) belonging to a user
+func GetAPIKeysByUser(ctx context.Context, userID primitive.ObjectID) ([]*APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := apiKeyCollection.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []*APIKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// RevokeAPIKeyForUser marks an API key as revoked, scoped to the given
+// owner, so a user can only revoke their own keys
+func RevokeAPIKeyForUser(ctx context.Context, id, userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := apiKeyCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "user_id": userID},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New(constants.MessageInvalidToken)
+	}
+	return nil
+}
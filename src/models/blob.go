@@ -0,0 +1,106 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/file-storage-api/src/config"
+	"github.com/yourusername/file-storage-api/src/constants"
+)
+
+// Blob records a single content-addressed copy of bytes shared by every
+// FileMetadata whose digest matches. RefCount tracks how many
+// FileMetadata documents currently point at it.
+type Blob struct {
+	Digest     string `bson:"_id" json:"digest"`
+	Size       int64  `bson:"size" json:"size"`
+	StorageKey string `bson:"storage_key" json:"-"`
+	RefCount   int64  `bson:"ref_count" json:"-"`
+}
+
+var blobCollection *mongo.Collection
+
+// InitBlobCollection initializes the blobs collection in MongoDB
+func InitBlobCollection() {
+	if client != nil {
+		blobCollection = client.Database(config.GetMongoDBName()).Collection("blobs")
+	}
+}
+
+// IncrementBlobRef records a new reference to digest, creating the blob
+// record (with ref_count 1) the first time digest is seen. It reports
+// whether the blob already existed, so the caller can skip re-writing bytes
+// that are already in the storage backend.
+func IncrementBlobRef(ctx context.Context, digest string, size int64, storageKey string) (existed bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var before Blob
+	err = blobCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": digest},
+		bson.M{
+			"$inc":         bson.M{"ref_count": 1},
+			"$setOnInsert": bson.M{"size": size, "storage_key": storageKey},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.Before),
+	).Decode(&before)
+
+	if err == mongo.ErrNoDocuments {
+		// No document existed prior to the upsert: this digest is new.
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// DecrementBlobRef drops a reference to digest, deleting the blob record
+// once its ref_count reaches zero. It reports whether the underlying bytes
+// should now be removed from the storage backend.
+func DecrementBlobRef(ctx context.Context, digest string) (shouldDelete bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var after Blob
+	err = blobCollection.FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": digest},
+		bson.M{"$inc": bson.M{"ref_count": -1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&after)
+	if err != nil {
+		return false, err
+	}
+
+	if after.RefCount <= 0 {
+		if _, err := blobCollection.DeleteOne(ctx, bson.M{"_id": digest}); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// GetBlobByDigest looks up a blob by its sha256 digest
+func GetBlobByDigest(ctx context.Context, digest string) (*Blob, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var blob Blob
+	err := blobCollection.FindOne(ctx, bson.M{"_id": digest}).Decode(&blob)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New(constants.MessageFileNotFound)
+		}
+		return nil, err
+	}
+
+	return &blob, nil
+}
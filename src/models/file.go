@@ -3,6 +3,8 @@ package models
 import (
 	"context"
 	"errors"
+	"regexp"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,15 +16,26 @@ import (
 	"file-storage-api/src/constants"
 )
 
-// FileMetadata represents metadata for a stored file
+// FileMetadata represents metadata for a stored file, or for a directory
+// when IsDir is true. Files and directories share one collection and are
+// organized into a tree via ParentID, with Path as a materialized,
+// slash-separated cache of that tree (e.g. "/docs/report.pdf") kept in sync
+// by CreateFileMetadata and MoveFile so lookups by path don't need to walk
+// ParentID chains.
 type FileMetadata struct {
-	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
-	FileName  string             `bson:"file_name" json:"file_name"`
-	FilePath  string             `bson:"file_path" json:"-"` // Internal path, not exposed in API
-	Size      int64              `bson:"size" json:"size"`
-	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
+	ParentID    primitive.ObjectID `bson:"parent_id" json:"parent_id"`        // primitive.NilObjectID means the storage root
+	IsDir       bool               `bson:"is_dir" json:"is_dir"`
+	FileName    string             `bson:"file_name" json:"file_name"`
+	Path        string             `bson:"path" json:"path"`                  // materialized, e.g. "/docs/report.pdf"
+	StorageKey  string             `bson:"storage_key" json:"-"`              // Opaque key within Backend, not exposed in API
+	Backend     string             `bson:"backend" json:"-"`                  // Storage driver StorageKey was written through, e.g. "localfs", "s3", "gridfs"
+	Digest      string             `bson:"digest" json:"digest"`              // sha256 of the file's contents
+	ContentType string             `bson:"content_type" json:"content_type"`
+	Size        int64              `bson:"size" json:"size"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
 var (
@@ -43,14 +56,36 @@ func InitFileCollection() {
 		}
 
 		_, _ = fileCollection.Indexes().CreateOne(ctx, indexModel)
+
+		// Enforce unique names within a parent directory (root included, via
+		// the zero-value ParentID), replacing the old global FileExistsByName
+		// check.
+		uniqueNameIndex := mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "user_id", Value: 1},
+				{Key: "parent_id", Value: 1},
+				{Key: "file_name", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		}
+
+		_, _ = fileCollection.Indexes().CreateOne(ctx, uniqueNameIndex)
 	}
 }
 
-// CreateFileMetadata saves file metadata to MongoDB
-func CreateFileMetadata(metadata *FileMetadata) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// CreateFileMetadata saves file metadata to MongoDB. Path is always
+// (re)computed from ParentID and FileName, so callers only need to set
+// ParentID (the zero value places the item at the storage root).
+func CreateFileMetadata(ctx context.Context, metadata *FileMetadata) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
+	parentPath, err := parentPathOf(ctx, metadata.UserID, metadata.ParentID)
+	if err != nil {
+		return err
+	}
+	metadata.Path = joinPath(parentPath, metadata.FileName)
+
 	// Set creation and update times
 	now := time.Now()
 	metadata.CreatedAt = now
@@ -59,6 +94,9 @@ func CreateFileMetadata(metadata *FileMetadata) error {
 	// Insert file metadata
 	result, err := fileCollection.InsertOne(ctx, metadata)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return errors.New(constants.MessageFileDuplicate)
+		}
 		return err
 	}
 
@@ -67,9 +105,38 @@ func CreateFileMetadata(metadata *FileMetadata) error {
 	return nil
 }
 
+// parentPathOf resolves parentID to the materialized Path it should prefix
+// a new child with. primitive.NilObjectID (the zero value) is the storage
+// root, whose path is "/". Any other parentID must name an existing
+// directory owned by userID.
+func parentPathOf(ctx context.Context, userID, parentID primitive.ObjectID) (string, error) {
+	if parentID.IsZero() {
+		return "/", nil
+	}
+
+	parent, err := GetFileByID(ctx, parentID, userID)
+	if err != nil {
+		return "", errors.New(constants.MessageFolderNotFound)
+	}
+	if !parent.IsDir {
+		return "", errors.New(constants.MessageInvalidParent)
+	}
+
+	return parent.Path, nil
+}
+
+// joinPath appends name to parentPath, both materialized, slash-separated
+// paths rooted at "/".
+func joinPath(parentPath, name string) string {
+	if parentPath == "/" {
+		return "/" + name
+	}
+	return parentPath + "/" + name
+}
+
 // GetFilesByUserID lists all files for a given user
-func GetFilesByUserID(userID primitive.ObjectID) ([]*FileMetadata, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func GetFilesByUserID(ctx context.Context, userID primitive.ObjectID) ([]*FileMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	cursor, err := fileCollection.Find(ctx, bson.M{"user_id": userID})
@@ -87,8 +154,8 @@ func GetFilesByUserID(userID primitive.ObjectID) ([]*FileMetadata, error) {
 }
 
 // GetFilesByUserIDPaginated lists files for a given user with pagination
-func GetFilesByUserIDPaginated(userID primitive.ObjectID, page, pageSize int) ([]*FileMetadata, int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func GetFilesByUserIDPaginated(ctx context.Context, userID primitive.ObjectID, page, pageSize int) ([]*FileMetadata, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	// Calculate skip value based on page and pageSize
@@ -121,8 +188,8 @@ func GetFilesByUserIDPaginated(userID primitive.ObjectID, page, pageSize int) ([
 }
 
 // GetFileByID retrieves a file by its ID
-func GetFileByID(fileID, userID primitive.ObjectID) (*FileMetadata, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func GetFileByID(ctx context.Context, fileID, userID primitive.ObjectID) (*FileMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var file FileMetadata
@@ -141,9 +208,28 @@ func GetFileByID(fileID, userID primitive.ObjectID) (*FileMetadata, error) {
 	return &file, nil
 }
 
+// GetFileByIDUnscoped retrieves a file by its ID regardless of owner. Used
+// for public, shared-link downloads where the caller isn't authenticated
+// as the owning user.
+func GetFileByIDUnscoped(ctx context.Context, fileID primitive.ObjectID) (*FileMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var file FileMetadata
+	err := fileCollection.FindOne(ctx, bson.M{"_id": fileID}).Decode(&file)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New(constants.MessageFileNotFound)
+		}
+		return nil, err
+	}
+
+	return &file, nil
+}
+
 // DeleteFileMetadata removes file metadata from MongoDB
-func DeleteFileMetadata(fileID, userID primitive.ObjectID) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func DeleteFileMetadata(ctx context.Context, fileID, userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	result, err := fileCollection.DeleteOne(ctx, bson.M{
@@ -162,19 +248,231 @@ func DeleteFileMetadata(fileID, userID primitive.ObjectID) error {
 	return nil
 }
 
-// FileExistsByName checks if a file with the given name already exists for the user
-func FileExistsByName(userID primitive.ObjectID, fileName string) (bool, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+// GetAllFileMetadata iterates every file's metadata regardless of owner.
+// Used by the migrate command to walk every file while moving its bytes
+// from one storage backend to another.
+func GetAllFileMetadata(ctx context.Context) ([]*FileMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cursor, err := fileCollection.Find(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var files []*FileMetadata
+	if err = cursor.All(ctx, &files); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// UpdateFileBackend records that fileID's bytes now live under newKey in
+// newBackend, after a successful migration between storage drivers.
+func UpdateFileBackend(ctx context.Context, fileID primitive.ObjectID, newBackend, newKey string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := fileCollection.UpdateOne(ctx,
+		bson.M{"_id": fileID},
+		bson.M{"$set": bson.M{"backend": newBackend, "storage_key": newKey, "updated_at": time.Now()}},
+	)
+	return err
+}
+
+// CreateDirectory creates a new, empty directory for userID under parentID
+// (primitive.NilObjectID for the storage root).
+func CreateDirectory(ctx context.Context, userID primitive.ObjectID, name string, parentID primitive.ObjectID) (*FileMetadata, error) {
+	dir := &FileMetadata{
+		UserID:   userID,
+		ParentID: parentID,
+		FileName: name,
+		IsDir:    true,
+	}
+
+	if err := CreateFileMetadata(ctx, dir); err != nil {
+		return nil, err
+	}
+
+	return dir, nil
+}
+
+// ListByParent lists the immediate children of parentID (files and
+// directories alike) owned by userID, with pagination.
+func ListByParent(ctx context.Context, userID, parentID primitive.ObjectID, page, pageSize int) ([]*FileMetadata, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"user_id": userID, "parent_id": parentID}
+
+	totalCount, err := fileCollection.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSkip(int64((page - 1) * pageSize))
+	findOptions.SetLimit(int64(pageSize))
+	findOptions.SetSort(bson.D{{Key: "is_dir", Value: -1}, {Key: "file_name", Value: 1}})
+
+	cursor, err := fileCollection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var children []*FileMetadata
+	if err = cursor.All(ctx, &children); err != nil {
+		return nil, 0, err
+	}
+
+	return children, totalCount, nil
+}
+
+// MoveFile relocates fileID to newParentID, recomputing its Path. If fileID
+// is a directory, every descendant's Path is rewritten in a single bulk
+// update so the whole subtree's materialized paths stay prefixed correctly.
+func MoveFile(ctx context.Context, fileID, userID, newParentID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	// Count files with the same name for this user
-	count, err := fileCollection.CountDocuments(ctx, bson.M{
-		"user_id":   userID,
-		"file_name": fileName,
+	file, err := GetFileByID(ctx, fileID, userID)
+	if err != nil {
+		return err
+	}
+
+	if fileID == newParentID {
+		return errors.New(constants.MessageCannotMoveIntoDescendant)
+	}
+
+	newParentPath, err := parentPathOf(ctx, userID, newParentID)
+	if err != nil {
+		return err
+	}
+
+	// A directory can't be moved into itself or into one of its own
+	// descendants: that would make parent_id pointers cycle back on
+	// themselves and rewrite Path into a self-referential mess.
+	if file.IsDir && (newParentPath == file.Path || strings.HasPrefix(newParentPath, file.Path+"/")) {
+		return errors.New(constants.MessageCannotMoveIntoDescendant)
+	}
+
+	newPath := joinPath(newParentPath, file.FileName)
+
+	if _, err := fileCollection.UpdateOne(ctx,
+		bson.M{"_id": fileID, "user_id": userID},
+		bson.M{"$set": bson.M{"parent_id": newParentID, "path": newPath, "updated_at": time.Now()}},
+	); err != nil {
+		return err
+	}
+
+	if !file.IsDir || file.Path == newPath {
+		return nil
+	}
+
+	return cascadeDescendantPaths(ctx, userID, file.Path, newPath)
+}
+
+// cascadeDescendantPaths rewrites the Path of every descendant of a moved
+// directory, replacing the oldPrefix each one starts with with newPrefix.
+func cascadeDescendantPaths(ctx context.Context, userID primitive.ObjectID, oldPrefix, newPrefix string) error {
+	cursor, err := fileCollection.Find(ctx, bson.M{
+		"user_id": userID,
+		"path":    bson.M{"$regex": "^" + regexp.QuoteMeta(oldPrefix+"/")},
 	})
 	if err != nil {
-		return false, err
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	var descendants []*FileMetadata
+	if err := cursor.All(ctx, &descendants); err != nil {
+		return err
+	}
+	if len(descendants) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(descendants))
+	for _, d := range descendants {
+		newPath := newPrefix + strings.TrimPrefix(d.Path, oldPrefix)
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": d.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"path": newPath, "updated_at": now}}))
+	}
+
+	_, err = fileCollection.BulkWrite(ctx, writes)
+	return err
+}
+
+// CopyFile duplicates a non-directory file under newParentID, pointing the
+// copy at the same underlying blob and bumping its reference count.
+func CopyFile(ctx context.Context, fileID, userID, newParentID primitive.ObjectID) (*FileMetadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	src, err := GetFileByID(ctx, fileID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if src.IsDir {
+		return nil, errors.New(constants.MessageCannotCopyDir)
+	}
+
+	// A copy charges the same quota as a fresh upload of the same bytes,
+	// even though the blob is deduplicated, so that copying is not a way to
+	// store unlimited data for free.
+	if err := UpdateStorageUsed(ctx, userID, src.Size); err != nil {
+		return nil, err
+	}
+
+	dup := &FileMetadata{
+		UserID:      userID,
+		ParentID:    newParentID,
+		FileName:    src.FileName,
+		StorageKey:  src.StorageKey,
+		Backend:     src.Backend,
+		Digest:      src.Digest,
+		ContentType: src.ContentType,
+		Size:        src.Size,
+	}
+
+	if err := CreateFileMetadata(ctx, dup); err != nil {
+		_ = UpdateStorageUsed(ctx, userID, -src.Size)
+		return nil, err
+	}
+
+	if _, err := IncrementBlobRef(ctx, src.Digest, src.Size, src.StorageKey); err != nil {
+		_ = DeleteFileMetadata(ctx, dup.ID, userID)
+		_ = UpdateStorageUsed(ctx, userID, -src.Size)
+		return nil, err
+	}
+
+	return dup, nil
+}
+
+// GetPathID resolves a materialized path to the ID of the file or directory
+// it names. "" and "/" both resolve to primitive.NilObjectID, the storage
+// root.
+func GetPathID(ctx context.Context, userID primitive.ObjectID, path string) (primitive.ObjectID, error) {
+	if path == "" || path == "/" {
+		return primitive.NilObjectID, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var file FileMetadata
+	err := fileCollection.FindOne(ctx, bson.M{"user_id": userID, "path": path}).Decode(&file)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return primitive.NilObjectID, errors.New(constants.MessageFolderNotFound)
+		}
+		return primitive.NilObjectID, err
 	}
 
-	return count > 0, nil
+	return file.ID, nil
 }
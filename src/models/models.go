@@ -39,8 +39,9 @@ type UserLogin struct {
 
 // AuthResponse is the response for authentication operations
 type AuthResponse struct {
-	Token string `json:"token"`
-	User  User   `json:"user"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	User         User   `json:"user"`
 }
 
 // ErrorResponse represents an error response
@@ -90,6 +91,13 @@ func InitMongoDB() error {
 	return err
 }
 
+// GetMongoClient returns the shared MongoDB client, for packages (like
+// storage's GridFS driver) that need to talk to Mongo directly instead of
+// through a model function.
+func GetMongoClient() *mongo.Client {
+	return client
+}
+
 // CloseMongoDB closes the MongoDB connection
 func CloseMongoDB() {
 	if client != nil {
@@ -100,8 +108,8 @@ func CloseMongoDB() {
 }
 
 // CreateUser creates a new user in MongoDB
-func CreateUser(user *User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func CreateUser(ctx context.Context, user *User) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	// Check if username already exists
@@ -130,8 +138,8 @@ func CreateUser(user *User) error {
 }
 
 // GetUserByID finds a user by ID
-func GetUserByID(id primitive.ObjectID) (*User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func GetUserByID(ctx context.Context, id primitive.ObjectID) (*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var user User
@@ -147,8 +155,8 @@ func GetUserByID(id primitive.ObjectID) (*User, error) {
 }
 
 // GetUserByUsername finds a user by username
-func GetUserByUsername(username string) (*User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func GetUserByUsername(ctx context.Context, username string) (*User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	var user User
@@ -164,12 +172,12 @@ func GetUserByUsername(username string) (*User, error) {
 }
 
 // UpdateStorageUsed updates the storage used by a user
-func UpdateStorageUsed(userID primitive.ObjectID, sizeChange int64) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func UpdateStorageUsed(ctx context.Context, userID primitive.ObjectID, sizeChange int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	// Get current user
-	user, err := GetUserByID(userID)
+	user, err := GetUserByID(ctx, userID)
 	if err != nil {
 		return err
 	}
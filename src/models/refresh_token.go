@@ -0,0 +1,130 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/file-storage-api/src/config"
+	"github.com/yourusername/file-storage-api/src/constants"
+)
+
+// RefreshToken represents one logged-in device/session. Only a hash of the
+// opaque refresh token is stored, so a database compromise doesn't hand an
+// attacker live sessions.
+type RefreshToken struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TokenHash string             `bson:"token_hash" json:"-"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+	Revoked   bool               `bson:"revoked" json:"revoked"`
+	UserAgent string             `bson:"user_agent" json:"user_agent"`
+	IP        string             `bson:"ip" json:"ip"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
+
+var refreshTokenCollection *mongo.Collection
+
+// InitRefreshTokenCollection initializes the refresh_tokens collection in MongoDB
+func InitRefreshTokenCollection() {
+	if client != nil {
+		refreshTokenCollection = client.Database(config.GetMongoDBName()).Collection("refresh_tokens")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		indexModel := mongo.IndexModel{
+			Keys: bson.D{{Key: "token_hash", Value: 1}},
+		}
+
+		_, _ = refreshTokenCollection.Indexes().CreateOne(ctx, indexModel)
+	}
+}
+
+// CreateRefreshToken persists a new refresh token
+func CreateRefreshToken(ctx context.Context, rt *RefreshToken) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	rt.CreatedAt = time.Now()
+
+	result, err := refreshTokenCollection.InsertOne(ctx, rt)
+	if err != nil {
+		return err
+	}
+
+	rt.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetRefreshTokenByHash looks up a refresh token by the hash of its
+// plaintext value. Revoked and expired tokens are returned too; callers
+// check Revoked and ExpiresAt themselves.
+func GetRefreshTokenByHash(ctx context.Context, tokenHash string) (*RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var rt RefreshToken
+	err := refreshTokenCollection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&rt)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New(constants.MessageInvalidToken)
+		}
+		return nil, err
+	}
+
+	return &rt, nil
+}
+
+// RevokeRefreshToken marks a refresh token as revoked by its ID
+func RevokeRefreshToken(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := refreshTokenCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	return err
+}
+
+// RevokeRefreshTokenForUser marks a refresh token as revoked, scoped to the
+// given owner, so a user can only revoke their own sessions
+func RevokeRefreshTokenForUser(ctx context.Context, id, userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := refreshTokenCollection.UpdateOne(ctx,
+		bson.M{"_id": id, "user_id": userID},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New(constants.MessageInvalidToken)
+	}
+	return nil
+}
+
+// GetRefreshTokensByUser lists every session (revoked or not) belonging to a user
+func GetRefreshTokensByUser(ctx context.Context, userID primitive.ObjectID) ([]*RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cursor, err := refreshTokenCollection.Find(ctx, bson.M{"user_id": userID})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tokens []*RefreshToken
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
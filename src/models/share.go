@@ -0,0 +1,274 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/yourusername/file-storage-api/src/config"
+	"github.com/yourusername/file-storage-api/src/constants"
+)
+
+// Share represents a public, unauthenticated link to download one file.
+type Share struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	TokenHash     string             `bson:"token_hash" json:"-"`
+	FileID        primitive.ObjectID `bson:"file_id" json:"file_id"`
+	OwnerID       primitive.ObjectID `bson:"owner_id" json:"-"`
+	ExpiresAt     time.Time          `bson:"expires_at" json:"expires_at"`
+	MaxDownloads  int                `bson:"max_downloads" json:"max_downloads"`
+	DownloadCount int                `bson:"download_count" json:"download_count"`
+	HitCount      int64              `bson:"hit_count" json:"hit_count"`
+	PasswordHash  string             `bson:"password_hash,omitempty" json:"-"`
+	DeleteKeyHash string             `bson:"delete_key_hash" json:"-"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+}
+
+var shareCollection *mongo.Collection
+
+// InitShareCollection initializes the shares collection in MongoDB
+func InitShareCollection() {
+	if client != nil {
+		shareCollection = client.Database(config.GetMongoDBName()).Collection("shares")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		indexModel := mongo.IndexModel{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		}
+
+		_, _ = shareCollection.Indexes().CreateOne(ctx, indexModel)
+
+		// Documents are removed automatically once their expires_at passes,
+		// so an abandoned share doesn't have to wait on StartShareSweeper.
+		ttlIndex := mongo.IndexModel{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(0),
+		}
+
+		_, _ = shareCollection.Indexes().CreateOne(ctx, ttlIndex)
+	}
+}
+
+// generateShareSecret returns a 22-char, base64url-encoded random value,
+// suitable for use as a share token or its one-time delete key.
+func generateShareSecret() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashShareSecret hashes a plaintext share token or delete key for
+// storage/lookup. Like refresh tokens and API keys, these are high-entropy
+// random values compared for exact equality, not human-chosen passwords, so
+// a fast, indexable hash is appropriate rather than bcrypt.
+func hashShareSecret(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateShare creates a time-limited, download-limited share link for
+// fileID, optionally gated by password. It returns the plaintext token
+// (usable as /s/:token) and a one-time delete key, neither of which is
+// recoverable afterwards: only their hashes are persisted.
+func CreateShare(ctx context.Context, fileID, ownerID primitive.ObjectID, expiresAt time.Time, maxDownloads int, password string) (token, deleteKey string, err error) {
+	token, err = generateShareSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	deleteKey, err = generateShareSecret()
+	if err != nil {
+		return "", "", err
+	}
+
+	var passwordHash string
+	if password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return "", "", err
+		}
+		passwordHash = string(hash)
+	}
+
+	share := &Share{
+		TokenHash:     hashShareSecret(token),
+		FileID:        fileID,
+		OwnerID:       ownerID,
+		ExpiresAt:     expiresAt,
+		MaxDownloads:  maxDownloads,
+		PasswordHash:  passwordHash,
+		DeleteKeyHash: hashShareSecret(deleteKey),
+		CreatedAt:     time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := shareCollection.InsertOne(ctx, share)
+	if err != nil {
+		return "", "", err
+	}
+	share.ID = result.InsertedID.(primitive.ObjectID)
+
+	return token, deleteKey, nil
+}
+
+// GetShareByToken looks up a share by its plaintext public token, hashing it
+// to match what's stored.
+func GetShareByToken(ctx context.Context, token string) (*Share, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var share Share
+	err := shareCollection.FindOne(ctx, bson.M{"token_hash": hashShareSecret(token)}).Decode(&share)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New(constants.MessageShareNotFound)
+		}
+		return nil, err
+	}
+
+	return &share, nil
+}
+
+// IncrementShareHit records one view of a share link, independent of
+// whether it goes on to count against MaxDownloads.
+func IncrementShareHit(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := shareCollection.UpdateOne(ctx,
+		bson.M{"_id": id},
+		bson.M{"$inc": bson.M{"hit_count": 1}},
+	)
+	return err
+}
+
+// ResolveShare validates a share link end-to-end and returns the file it
+// points at: looks the token up, records a view, checks expiry and the
+// optional password, then atomically consumes one download against
+// MaxDownloads so concurrent requests can't push DownloadCount past it.
+func ResolveShare(ctx context.Context, token, password string) (*FileMetadata, *Share, error) {
+	share, err := GetShareByToken(ctx, token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := IncrementShareHit(ctx, share.ID); err != nil {
+		return nil, nil, err
+	}
+
+	if time.Now().After(share.ExpiresAt) {
+		return nil, nil, errors.New(constants.MessageShareExpired)
+	}
+
+	if share.PasswordHash != "" {
+		if password == "" || bcrypt.CompareHashAndPassword([]byte(share.PasswordHash), []byte(password)) != nil {
+			return nil, nil, errors.New(constants.MessageSharePasswordBad)
+		}
+	}
+
+	if err := consumeShareDownload(ctx, share.ID); err != nil {
+		return nil, nil, err
+	}
+
+	file, err := GetFileByIDUnscoped(ctx, share.FileID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, share, nil
+}
+
+// consumeShareDownload increments download_count only if doing so keeps it
+// at or below max_downloads, evaluated and applied atomically so concurrent
+// downloads of an almost-exhausted share can't race past the limit.
+func consumeShareDownload(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := shareCollection.UpdateOne(ctx,
+		bson.M{
+			"_id":   id,
+			"$expr": bson.M{"$lt": []interface{}{"$download_count", "$max_downloads"}},
+		},
+		bson.M{"$inc": bson.M{"download_count": 1}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount == 0 {
+		return errors.New(constants.MessageShareExhausted)
+	}
+	return nil
+}
+
+// RevokeShare deletes a share once its one-time delete key is presented.
+func RevokeShare(ctx context.Context, token, deleteKey string) error {
+	share, err := GetShareByToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashShareSecret(deleteKey)), []byte(share.DeleteKeyHash)) != 1 {
+		return errors.New(constants.MessageShareDeleteKeyBad)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := shareCollection.DeleteOne(ctx, bson.M{"_id": share.ID}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PurgeExpiredShares deletes every share whose expiry has passed, returning
+// how many were removed. The TTL index on expires_at (see
+// InitShareCollection) handles this in the common case; this sweeper is a
+// backstop for deployments where MongoDB's background TTL pass lags.
+func PurgeExpiredShares(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := shareCollection.DeleteMany(ctx, bson.M{"expires_at": bson.M{"$lt": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// StartShareSweeper periodically purges expired shares in the background.
+// It never returns; callers should launch it with `go`.
+func StartShareSweeper(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		deleted, err := PurgeExpiredShares(context.Background())
+		if err != nil {
+			log.Printf("share sweeper: failed to purge expired shares: %v", err)
+			continue
+		}
+		if deleted > 0 {
+			log.Printf("share sweeper: purged %d expired share(s)", deleted)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yourusername/file-storage-api/src/config"
+	"github.com/yourusername/file-storage-api/src/constants"
+)
+
+// UploadSession tracks a resumable upload's progress so a client can
+// continue appending bytes after a dropped connection or server restart.
+//
+// Two upload flows share this one session: appending a growing byte range
+// via Offset (AppendChunk/FinalizeUpload), and uploading fixed-size,
+// independently-addressed chunks identified by index (UploadChunk), tracked
+// by ReceivedBitmap once TotalSize/ChunkSize are set at creation time.
+type UploadSession struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID         primitive.ObjectID `bson:"user_id" json:"user_id"`
+	FileName       string             `bson:"file_name" json:"file_name"`
+	Offset         int64              `bson:"offset" json:"offset"`
+	TotalSize      int64              `bson:"total_size,omitempty" json:"total_size,omitempty"`
+	ChunkSize      int64              `bson:"chunk_size,omitempty" json:"chunk_size,omitempty"`
+	ReceivedBitmap []bool             `bson:"received_bitmap,omitempty" json:"-"`
+	StagingPath    string             `bson:"staging_path" json:"-"`
+	ExpiresAt      time.Time          `bson:"expires_at" json:"expires_at"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// NumChunks returns how many fixed-size chunks TotalSize splits into given
+// ChunkSize. Zero if the session isn't using the indexed-chunk flow.
+func (s *UploadSession) NumChunks() int64 {
+	if s.ChunkSize <= 0 {
+		return 0
+	}
+	return (s.TotalSize + s.ChunkSize - 1) / s.ChunkSize
+}
+
+// AllChunksReceived reports whether every chunk in ReceivedBitmap is set.
+func (s *UploadSession) AllChunksReceived() bool {
+	if len(s.ReceivedBitmap) == 0 {
+		return false
+	}
+	for _, received := range s.ReceivedBitmap {
+		if !received {
+			return false
+		}
+	}
+	return true
+}
+
+var uploadSessionCollection *mongo.Collection
+
+// InitUploadSessionCollection initializes the upload_sessions collection in MongoDB
+func InitUploadSessionCollection() {
+	if client != nil {
+		uploadSessionCollection = client.Database(config.GetMongoDBName()).Collection("upload_sessions")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		indexModel := mongo.IndexModel{
+			Keys: bson.D{{Key: "user_id", Value: 1}},
+		}
+
+		_, _ = uploadSessionCollection.Indexes().CreateOne(ctx, indexModel)
+	}
+}
+
+// CreateUploadSession persists a new upload session
+func CreateUploadSession(ctx context.Context, session *UploadSession) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	session.CreatedAt = now
+	session.UpdatedAt = now
+
+	result, err := uploadSessionCollection.InsertOne(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	session.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetUploadSession retrieves an upload session owned by userID
+func GetUploadSession(ctx context.Context, id, userID primitive.ObjectID) (*UploadSession, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var session UploadSession
+	err := uploadSessionCollection.FindOne(ctx, bson.M{
+		"_id":     id,
+		"user_id": userID,
+	}).Decode(&session)
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, errors.New(constants.MessageUploadSessionNotFound)
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// UpdateUploadSessionOffset records how many bytes have been received so far
+func UpdateUploadSessionOffset(ctx context.Context, id primitive.ObjectID, offset int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := uploadSessionCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{
+			"offset":     offset,
+			"updated_at": time.Now(),
+		},
+	})
+	return err
+}
+
+// MarkChunkReceived flips the bit for the chunk at index in a session's
+// ReceivedBitmap. Each index is its own array field path, so concurrent
+// chunk uploads marking different indices don't race.
+func MarkChunkReceived(ctx context.Context, id primitive.ObjectID, index int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	_, err := uploadSessionCollection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$set": bson.M{
+			"received_bitmap." + strconv.FormatInt(index, 10): true,
+			"updated_at": time.Now(),
+		},
+	})
+	return err
+}
+
+// DeleteUploadSession removes an upload session owned by userID
+func DeleteUploadSession(ctx context.Context, id, userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result, err := uploadSessionCollection.DeleteOne(ctx, bson.M{
+		"_id":     id,
+		"user_id": userID,
+	})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New(constants.MessageUploadSessionNotFound)
+	}
+	return nil
+}
+
+// PurgeExpiredUploadSessions deletes every upload session whose expiry has
+// passed, along with its staging ".part" file, returning how many were
+// removed.
+func PurgeExpiredUploadSessions(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := uploadSessionCollection.Find(ctx, bson.M{"expires_at": bson.M{"$lt": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var sessions []*UploadSession
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return 0, err
+	}
+	if len(sessions) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(sessions))
+	for i, s := range sessions {
+		ids[i] = s.ID
+	}
+
+	if _, err := uploadSessionCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return 0, err
+	}
+
+	for _, s := range sessions {
+		_ = os.Remove(s.StagingPath)
+	}
+
+	return int64(len(sessions)), nil
+}
+
+// StartUploadSessionJanitor periodically purges expired upload sessions and
+// their staging files in the background. It never returns; callers should
+// launch it with `go`.
+func StartUploadSessionJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		purged, err := PurgeExpiredUploadSessions(context.Background())
+		if err != nil {
+			log.Printf("upload session janitor: failed to purge expired sessions: %v", err)
+			continue
+		}
+		if purged > 0 {
+			log.Printf("upload session janitor: purged %d expired upload session(s)", purged)
+		}
+	}
+}
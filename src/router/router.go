@@ -1,45 +1,131 @@
 package router
 
 import (
+	"log"
+	"time"
+
 	"github.com/gin-gonic/gin"
 
+	"file-storage-api/src/auth"
+	"file-storage-api/src/config"
 	"file-storage-api/src/handlers"
 	"file-storage-api/src/storage"
 )
 
+// buildAuthers constructs the ordered chain of Authers configured under
+// auth.providers in config.yml
+func buildAuthers() []auth.Auther {
+	providers := config.GetAuthProviders()
+	authers := make([]auth.Auther, 0, len(providers))
+	for _, name := range providers {
+		a, err := auth.NewAuther(name)
+		if err != nil {
+			log.Fatalf("invalid auth provider %q: %v", name, err)
+		}
+		authers = append(authers, a)
+	}
+	return authers
+}
+
 // SetupRouter sets up the API routes
 func SetupRouter(fileService *storage.FileService) *gin.Engine {
 	// Set Gin to release mode to suppress debug output
 	gin.SetMode(gin.ReleaseMode)
 
-	router := gin.Default()
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(handlers.RequestID())
+	router.Use(handlers.RequestLogger())
+	// RequestTimeout is applied selectively below, not globally: it's meant
+	// to bound metadata/DB handlers, not the upload/download/share-download
+	// routes that stream a request or response body, which can legitimately
+	// run past the configured timeout (large or slow mobile uploads, video
+	// range requests) and should only end on client disconnect.
 
 	// Create handlers
 	authHandler := &handlers.AuthHandler{}
+	apiKeyHandler := &handlers.APIKeyHandler{}
+	folderHandler := &handlers.FolderHandler{}
 	fileHandler := handlers.NewFileHandler(fileService)
+	uploadHandler := handlers.NewUploadHandler(fileService)
+	shareHandler := handlers.NewShareHandler(fileService)
+	authers := buildAuthers()
 
 	// API group
 	api := router.Group("/api")
 
 	// Public routes
-	api.POST("/register", authHandler.RegisterUser)
-	api.POST("/login", authHandler.LoginUser)
+	publicTimed := api.Group("")
+	publicTimed.Use(handlers.RequestTimeout())
+	{
+		publicTimed.POST("/register", authHandler.RegisterUser)
+		publicTimed.POST("/login", authHandler.LoginUser)
+		publicTimed.POST("/auth/refresh", authHandler.RefreshToken)
+		publicTimed.POST("/auth/logout", authHandler.Logout)
+	}
 
 	// Protected routes
 	protected := api.Group("")
-	protected.Use(handlers.AuthMiddleware())
+	protected.Use(handlers.AuthMiddleware(authers...))
 	{
-		protected.GET("/me", authHandler.GetUserInfo)
+		// Metadata/DB-only handlers get a hard request timeout.
+		timed := protected.Group("")
+		timed.Use(handlers.RequestTimeout())
+		{
+			timed.GET("/me", authHandler.GetUserInfo)
+			timed.GET("/auth/sessions", authHandler.GetSessions)
+			timed.DELETE("/auth/sessions/:id", authHandler.DeleteSession)
+
+			// API key management
+			timed.POST("/api-keys", apiKeyHandler.CreateAPIKey)
+			timed.GET("/api-keys", apiKeyHandler.ListAPIKeys)
+			timed.DELETE("/api-keys/:id", apiKeyHandler.RevokeAPIKey)
 
-		// File routes
+			// File routes
+			timed.GET("/files", fileHandler.ListFiles)
+			timed.GET("/files/:id", fileHandler.GetFile)
+			timed.GET("/files/by-digest/:sha256", fileHandler.GetFileByDigest)
+			timed.DELETE("/files/:id", fileHandler.DeleteFile)
+			timed.POST("/files/:id/move", fileHandler.MoveFile)
+			timed.POST("/files/:id/copy", fileHandler.CopyFile)
+
+			// Folder routes
+			timed.POST("/folders", folderHandler.CreateFolder)
+			timed.GET("/folders/:id/children", folderHandler.ListChildren)
+
+			// Resumable chunked upload routes: session bookkeeping only
+			timed.POST("/files/uploads", uploadHandler.CreateUploadSession)
+			timed.GET("/files/uploads/:id", uploadHandler.GetUploadStatus)
+			timed.DELETE("/files/uploads/:id", uploadHandler.AbortUpload)
+
+			// Storage routes
+			timed.GET("/storage/remaining", fileHandler.GetRemainingStorage)
+
+			// Share routes (creation requires ownership of the file)
+			timed.POST("/files/:id/shares", shareHandler.CreateShare)
+		}
+
+		// Streaming routes: request/response bodies can be large or slow
+		// (mobile uploads, video range requests), so these are exempt from
+		// RequestTimeout and rely on client disconnect instead of a fixed cap.
 		protected.POST("/files", fileHandler.UploadFile)
-		protected.GET("/files", fileHandler.ListFiles)
-		protected.GET("/files/:id", fileHandler.GetFile)
 		protected.GET("/files/:id/download", fileHandler.DownloadFile)
-		protected.DELETE("/files/:id", fileHandler.DeleteFile)
+		protected.GET("/files/:id/verify", fileHandler.VerifyFile)
+		protected.PATCH("/files/uploads/:id", uploadHandler.AppendChunk)
+		protected.PUT("/files/uploads/:id", uploadHandler.FinalizeUpload)
+		protected.PUT("/files/uploads/:id/chunks/:index", uploadHandler.UploadChunk)
+		protected.POST("/files/uploads/:id/complete", uploadHandler.CompleteUpload)
+	}
 
-		// Storage routes
-		protected.GET("/storage/remaining", fileHandler.GetRemainingStorage)
+	// Public share routes: no JWT auth, rate-limited by IP to deter
+	// enumeration of share tokens. DownloadShare streams a file body, so it
+	// is exempt from RequestTimeout like the other streaming routes above;
+	// RevokeShare is a metadata-only delete, so it keeps the hard timeout.
+	shares := router.Group("/s")
+	shares.Use(handlers.RateLimitByIP(30, time.Minute))
+	{
+		shares.GET("/:token", shareHandler.DownloadShare)
+		shares.DELETE("/:token", handlers.RequestTimeout(), shareHandler.RevokeShare)
 	}
 
 	return router
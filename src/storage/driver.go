@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// FileInfo describes a stored object as reported by a driver, independent
+// of where the bytes actually live.
+type FileInfo struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Driver is implemented by storage backends. Handlers and FileService talk
+// only to a Driver, so the rest of the application never needs to know
+// whether bytes live on local disk, S3, or GCS.
+type Driver interface {
+	// Put writes r to key, returning the number of bytes written.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Get opens key for reading. The caller must close the returned reader.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Stat returns metadata for key without reading its contents.
+	Stat(ctx context.Context, key string) (FileInfo, error)
+	// Delete removes key. Deleting a key that does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+	// GetRange opens key for reading starting at offset, limited to length
+	// bytes, so callers can serve partial content (HTTP Range requests)
+	// without pulling the whole object through the backend first.
+	GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+}
+
+// Factory builds a Driver from backend-specific configuration.
+type Factory func(config map[string]interface{}) (Driver, error)
+
+var drivers = map[string]Factory{}
+
+// Register makes a driver factory available under name. Third-party
+// backends (Swift, B2, ...) can plug in by calling Register from an
+// init() function without modifying this package.
+func Register(name string, factory Factory) {
+	drivers[name] = factory
+}
+
+// NewDriver constructs the driver registered under name using config.
+func NewDriver(name string, config map[string]interface{}) (Driver, error) {
+	factory, ok := drivers[name]
+	if !ok {
+		return nil, fmt.Errorf("storage: no driver registered for %q", name)
+	}
+	return factory(config)
+}
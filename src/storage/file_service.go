@@ -1,65 +1,65 @@
 package storage
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"os"
-	"path/filepath"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"github.com/yourusername/file-storage-api/src/config"
 	"github.com/yourusername/file-storage-api/src/constants"
+	"github.com/yourusername/file-storage-api/src/logging"
 	"github.com/yourusername/file-storage-api/src/models"
 )
 
-// FileService manages file storage operations
+// FileService manages file storage operations. It does not know where
+// bytes actually live; all I/O goes through a Driver. Every file uploaded
+// through this FileService is stamped with the backend's name (driverName)
+// so that once an operator starts migrating files to a new storage.driver
+// (see the migrate command), it's possible to tell which files still need
+// to move.
 type FileService struct {
-	storagePath string
+	driver     Driver
+	driverName string
 }
 
-// NewFileService creates a new FileService
+// NewFileService creates a new FileService backed by the driver configured
+// in config.AppConfig.Storage.Driver.
 func NewFileService() (*FileService, error) {
-	storagePath := config.GetStoragePath()
-
-	// Ensure storage path exists
-	if err := os.MkdirAll(storagePath, 0755); err != nil {
-		return nil, fmt.Errorf("could not create storage directory: %w", err)
+	name := config.GetStorageDriver()
+	driver, err := NewDriver(name, config.GetStorageDriverConfig())
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize storage driver: %w", err)
 	}
 
 	return &FileService{
-		storagePath: storagePath,
+		driver:     driver,
+		driverName: name,
 	}, nil
 }
 
-// createUserDirectory creates a directory for a user if it doesn't exist
-func (s *FileService) createUserDirectory(username string) (string, error) {
-	userDir := filepath.Join(s.storagePath, username)
-	if err := os.MkdirAll(userDir, 0755); err != nil {
-		return "", fmt.Errorf("could not create user directory: %w", err)
-	}
-	return userDir, nil
+// blobKey builds the driver-independent key a content-addressed blob is
+// stored under, keyed by its sha256 digest rather than by user/filename so
+// identical uploads from any user share one copy on disk.
+func blobKey(digest string) string {
+	return "blobs/" + digest
 }
 
-// UploadFile stores a file for a user
-func (s *FileService) UploadFile(userID primitive.ObjectID, fileName string, fileData io.Reader) (*models.FileMetadata, error) {
+// UploadFile stores a file for a user. Bytes are deduplicated by sha256
+// digest: if another file with the same contents already exists, only a new
+// FileMetadata row is created and the blob's reference count is bumped.
+func (s *FileService) UploadFile(ctx context.Context, userID primitive.ObjectID, fileName, contentType string, fileData io.Reader) (*models.FileMetadata, error) {
 	// Check if user exists
-	user, err := models.GetUserByID(userID)
-	if err != nil {
+	if _, err := models.GetUserByID(ctx, userID); err != nil {
 		return nil, errors.New(constants.MessageUserNotFound)
 	}
 
-	// Create user directory if it doesn't exist
-	userDir, err := s.createUserDirectory(user.Username)
-	if err != nil {
-		return nil, err
-	}
-
-	// Create the file path
-	filePath := filepath.Join(userDir, fileName)
-
-	// Create a temporary file to calculate size before adding to quota
+	// Create a temporary file to calculate size and digest before adding to quota
 	tmpFile, err := os.CreateTemp("", "upload-*")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temporary file: %w", err)
@@ -67,96 +67,166 @@ func (s *FileService) UploadFile(userID primitive.ObjectID, fileName string, fil
 	defer os.Remove(tmpFile.Name())
 	defer tmpFile.Close()
 
-	// Copy file data to temporary file to get size
-	size, err := io.Copy(tmpFile, fileData)
+	// Copy file data to temporary file while hashing it, to get size and digest
+	hasher := sha256.New()
+	size, err := io.Copy(tmpFile, io.TeeReader(fileData, hasher))
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file data: %w", err)
 	}
+	digest := hex.EncodeToString(hasher.Sum(nil))
 
-	// Check if this would exceed user's storage limit
-	if err := models.UpdateStorageUsed(userID, size); err != nil {
+	// Check if this would exceed user's storage limit. Every user is
+	// charged the logical file size regardless of dedup, so one user's
+	// quota isn't reduced by another user's lucky duplicate.
+	if err := models.UpdateStorageUsed(ctx, userID, size); err != nil {
 		return nil, err
 	}
 
-	// Reset file position
-	if _, err := tmpFile.Seek(0, 0); err != nil {
-		// Rollback storage usage update
-		_ = models.UpdateStorageUsed(userID, -size)
-		return nil, fmt.Errorf("failed to reset file position: %w", err)
-	}
+	key := blobKey(digest)
 
-	// Create the destination file
-	destFile, err := os.Create(filePath)
+	existed, err := models.IncrementBlobRef(ctx, digest, size, key)
 	if err != nil {
-		// Rollback storage usage update
-		_ = models.UpdateStorageUsed(userID, -size)
-		return nil, fmt.Errorf("failed to create file: %w", err)
+		_ = models.UpdateStorageUsed(ctx, userID, -size)
+		return nil, fmt.Errorf("failed to register blob: %w", err)
 	}
-	defer destFile.Close()
 
-	// Copy from temp file to destination
-	if _, err := io.Copy(destFile, tmpFile); err != nil {
-		// Rollback storage usage update
-		_ = models.UpdateStorageUsed(userID, -size)
-		// Try to remove the failed file
-		_ = os.Remove(filePath)
-		return nil, fmt.Errorf("failed to save file: %w", err)
+	if !existed {
+		// Reset file position and write the bytes through the storage driver
+		if _, err := tmpFile.Seek(0, 0); err != nil {
+			_ = models.UpdateStorageUsed(ctx, userID, -size)
+			_, _ = models.DecrementBlobRef(ctx, digest)
+			return nil, fmt.Errorf("failed to reset file position: %w", err)
+		}
+
+		if _, err := s.driver.Put(ctx, key, tmpFile); err != nil {
+			_ = models.UpdateStorageUsed(ctx, userID, -size)
+			_, _ = models.DecrementBlobRef(ctx, digest)
+			logging.Logger.Error("failed to write blob to storage driver", "digest", digest, "user_id", userID.Hex(), "error", err)
+			return nil, fmt.Errorf("failed to save file: %w", err)
+		}
 	}
 
 	// Create file metadata in MongoDB
 	metadata := &models.FileMetadata{
-		UserID:   userID,
-		FileName: fileName,
-		FilePath: filePath,
-		Size:     size,
-	}
-
-	if err := models.CreateFileMetadata(metadata); err != nil {
-		// Rollback storage usage update
-		_ = models.UpdateStorageUsed(userID, -size)
-		// Try to remove the failed file
-		_ = os.Remove(filePath)
+		UserID:      userID,
+		FileName:    fileName,
+		StorageKey:  key,
+		Backend:     s.driverName,
+		Digest:      digest,
+		ContentType: contentType,
+		Size:        size,
+	}
+
+	if err := models.CreateFileMetadata(ctx, metadata); err != nil {
+		_ = models.UpdateStorageUsed(ctx, userID, -size)
+		if shouldDelete, derr := models.DecrementBlobRef(ctx, digest); derr == nil && shouldDelete {
+			_ = s.driver.Delete(ctx, key)
+		}
+		if err.Error() == constants.MessageFileDuplicate {
+			return nil, err
+		}
+		logging.Logger.Error("failed to save file metadata", "user_id", userID.Hex(), "file_name", fileName, "error", err)
 		return nil, fmt.Errorf("failed to save file metadata: %w", err)
 	}
 
 	return metadata, nil
 }
 
-// DeleteFile removes a file
-func (s *FileService) DeleteFile(fileID, userID primitive.ObjectID) error {
+// DeleteFile removes a file. The underlying blob is only removed from the
+// storage backend once its last reference is gone.
+func (s *FileService) DeleteFile(ctx context.Context, fileID, userID primitive.ObjectID) error {
 	// Get file metadata
-	file, err := models.GetFileByID(fileID, userID)
+	file, err := models.GetFileByID(ctx, fileID, userID)
 	if err != nil {
 		return err
 	}
 
-	// Delete the file from storage
-	if err := os.Remove(file.FilePath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to delete file: %w", err)
+	shouldDelete, err := models.DecrementBlobRef(ctx, file.Digest)
+	if err != nil {
+		return fmt.Errorf("failed to release blob reference: %w", err)
+	}
+
+	if shouldDelete {
+		if err := s.driver.Delete(ctx, file.StorageKey); err != nil {
+			logging.Logger.Error("failed to delete blob from storage driver", "digest", file.Digest, "user_id", userID.Hex(), "error", err)
+			return fmt.Errorf("failed to delete file: %w", err)
+		}
 	}
 
 	// Update user's storage usage
-	if err := models.UpdateStorageUsed(userID, -file.Size); err != nil {
+	if err := models.UpdateStorageUsed(ctx, userID, -file.Size); err != nil {
 		return err
 	}
 
 	// Remove file metadata from MongoDB
-	return models.DeleteFileMetadata(fileID, userID)
+	return models.DeleteFileMetadata(ctx, fileID, userID)
 }
 
-// DownloadFile opens a file for download
-func (s *FileService) DownloadFile(fileID, userID primitive.ObjectID) (string, *os.File, error) {
+// DownloadFile opens a file for download in full, returning its name,
+// digest (usable as an ETag), and size alongside the content reader.
+func (s *FileService) DownloadFile(ctx context.Context, fileID, userID primitive.ObjectID) (name, digest string, size int64, rc io.ReadCloser, err error) {
 	// Get file metadata
-	file, err := models.GetFileByID(fileID, userID)
+	file, err := models.GetFileByID(ctx, fileID, userID)
+	if err != nil {
+		return "", "", 0, nil, err
+	}
+
+	// Open the file through the storage driver
+	rc, err = s.driver.Get(ctx, file.StorageKey)
 	if err != nil {
-		return "", nil, err
+		return "", "", 0, nil, fmt.Errorf("failed to open file: %w", err)
 	}
 
-	// Open the file
-	f, err := os.Open(file.FilePath)
+	return file.FileName, file.Digest, file.Size, rc, nil
+}
+
+// DownloadFileRange opens a byte range of a file, for HTTP Range requests
+// (partial downloads, seeking within streamed media).
+func (s *FileService) DownloadFileRange(ctx context.Context, fileID, userID primitive.ObjectID, offset, length int64) (name, digest string, size int64, rc io.ReadCloser, err error) {
+	file, err := models.GetFileByID(ctx, fileID, userID)
+	if err != nil {
+		return "", "", 0, nil, err
+	}
+
+	rc, err = s.driver.GetRange(ctx, file.StorageKey, offset, length)
+	if err != nil {
+		return "", "", 0, nil, fmt.Errorf("failed to open file range: %w", err)
+	}
+
+	return file.FileName, file.Digest, file.Size, rc, nil
+}
+
+// VerifyFile recomputes the sha256 of a stored file's bytes and compares it
+// against the digest recorded at upload time, catching silent corruption or
+// tampering in the storage backend.
+func (s *FileService) VerifyFile(ctx context.Context, fileID, userID primitive.ObjectID) (ok bool, digest string, err error) {
+	file, err := models.GetFileByID(ctx, fileID, userID)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to open file: %w", err)
+		return false, "", err
 	}
 
-	return file.FileName, f, nil
+	rc, err := s.driver.Get(ctx, file.StorageKey)
+	if err != nil {
+		return false, "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer rc.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, rc); err != nil {
+		return false, "", fmt.Errorf("failed to read file: %w", err)
+	}
+
+	actual := hex.EncodeToString(hasher.Sum(nil))
+	return actual == file.Digest, actual, nil
+}
+
+// OpenByKey opens an object directly by its storage key, bypassing the
+// per-user ownership lookup. Used for public, shared-link downloads that
+// have already authorized access through other means (e.g. a share token).
+func (s *FileService) OpenByKey(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := s.driver.Get(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	return rc, nil
 }
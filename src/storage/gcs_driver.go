@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+)
+
+// gcsDriver stores objects in a single Google Cloud Storage bucket.
+type gcsDriver struct {
+	client *storage.Client
+	bucket string
+}
+
+func init() {
+	Register("gcs", func(config map[string]interface{}) (Driver, error) {
+		bucket, _ := config["bucket"].(string)
+		if bucket == "" {
+			return nil, fmt.Errorf("gcs: %q is required", "bucket")
+		}
+
+		var opts []option.ClientOption
+		if credsFile, _ := config["credentials_file"].(string); credsFile != "" {
+			opts = append(opts, option.WithCredentialsFile(credsFile))
+		}
+
+		client, err := storage.NewClient(context.Background(), opts...)
+		if err != nil {
+			return nil, fmt.Errorf("gcs: could not create client: %w", err)
+		}
+
+		return &gcsDriver{client: client, bucket: bucket}, nil
+	})
+}
+
+func (d *gcsDriver) object(key string) *storage.ObjectHandle {
+	return d.client.Bucket(d.bucket).Object(key)
+}
+
+func (d *gcsDriver) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	w := d.object(key).NewWriter(ctx)
+	size, err := io.Copy(w, r)
+	if err != nil {
+		_ = w.Close()
+		return 0, fmt.Errorf("gcs: could not write object: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return 0, fmt.Errorf("gcs: could not finalize object: %w", err)
+	}
+	return size, nil
+}
+
+func (d *gcsDriver) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	r, err := d.object(key).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("gcs: could not read object: %w", err)
+	}
+	return r, nil
+}
+
+func (d *gcsDriver) Stat(ctx context.Context, key string) (FileInfo, error) {
+	attrs, err := d.object(key).Attrs(ctx)
+	if err != nil {
+		return FileInfo{}, fmt.Errorf("gcs: could not stat object: %w", err)
+	}
+	return FileInfo{Key: key, Size: attrs.Size, ModTime: attrs.Updated}, nil
+}
+
+func (d *gcsDriver) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	r, err := d.object(key).NewRangeReader(ctx, offset, length)
+	if err != nil {
+		return nil, fmt.Errorf("gcs: could not read object range: %w", err)
+	}
+	return r, nil
+}
+
+func (d *gcsDriver) Delete(ctx context.Context, key string) error {
+	if err := d.object(key).Delete(ctx); err != nil && !errors.Is(err, storage.ErrObjectNotExist) {
+		return fmt.Errorf("gcs: could not delete object: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/yourusername/file-storage-api/src/config"
+	"github.com/yourusername/file-storage-api/src/models"
+)
+
+// gridfsDriver stores objects in a MongoDB GridFS bucket, reusing the same
+// client and database the rest of the application already connects with.
+type gridfsDriver struct {
+	bucket *gridfs.Bucket
+}
+
+func init() {
+	Register("gridfs", func(cfg map[string]interface{}) (Driver, error) {
+		client := models.GetMongoClient()
+		if client == nil {
+			return nil, fmt.Errorf("gridfs: MongoDB must be initialized before the gridfs driver")
+		}
+
+		bucketName, _ := cfg["bucket"].(string)
+		if bucketName == "" {
+			bucketName = "fs"
+		}
+
+		db := client.Database(config.GetMongoDBName())
+		bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(bucketName))
+		if err != nil {
+			return nil, fmt.Errorf("gridfs: could not open bucket: %w", err)
+		}
+
+		return &gridfsDriver{bucket: bucket}, nil
+	})
+}
+
+func (d *gridfsDriver) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	// Re-uploading the same key would leave the old revision behind under a
+	// new file ID, so drop any existing copy first to keep one live
+	// revision per key, same as every other driver.
+	_ = d.delete(ctx, key)
+
+	stream, err := d.bucket.OpenUploadStream(key)
+	if err != nil {
+		return 0, fmt.Errorf("gridfs: could not open upload stream: %w", err)
+	}
+	defer stream.Close()
+
+	size, err := io.Copy(stream, r)
+	if err != nil {
+		return 0, fmt.Errorf("gridfs: could not write object: %w", err)
+	}
+	return size, nil
+}
+
+func (d *gridfsDriver) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	stream, err := d.bucket.OpenDownloadStreamByName(key)
+	if err != nil {
+		return nil, fmt.Errorf("gridfs: could not open download stream: %w", err)
+	}
+	return stream, nil
+}
+
+func (d *gridfsDriver) Stat(ctx context.Context, key string) (FileInfo, error) {
+	cursor, err := d.bucket.Find(bson.M{"filename": key})
+	if err != nil {
+		return FileInfo{}, fmt.Errorf("gridfs: could not stat object: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var file struct {
+		Length     int64     `bson:"length"`
+		UploadDate time.Time `bson:"uploadDate"`
+	}
+	if !cursor.Next(ctx) {
+		return FileInfo{}, fmt.Errorf("gridfs: object %q not found", key)
+	}
+	if err := cursor.Decode(&file); err != nil {
+		return FileInfo{}, fmt.Errorf("gridfs: could not decode object metadata: %w", err)
+	}
+
+	return FileInfo{Key: key, Size: file.Length, ModTime: file.UploadDate}, nil
+}
+
+func (d *gridfsDriver) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	stream, err := d.bucket.OpenDownloadStreamByName(key)
+	if err != nil {
+		return nil, fmt.Errorf("gridfs: could not open download stream: %w", err)
+	}
+
+	if _, err := stream.Skip(offset); err != nil {
+		stream.Close()
+		return nil, fmt.Errorf("gridfs: could not seek object: %w", err)
+	}
+
+	return &limitedReadCloser{r: io.LimitReader(stream, length), c: stream}, nil
+}
+
+func (d *gridfsDriver) Delete(ctx context.Context, key string) error {
+	return d.delete(ctx, key)
+}
+
+// delete removes every GridFS revision stored under key.
+func (d *gridfsDriver) delete(ctx context.Context, key string) error {
+	cursor, err := d.bucket.Find(bson.M{"filename": key})
+	if err != nil {
+		return fmt.Errorf("gridfs: could not look up object: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var ids []interface{}
+	for cursor.Next(ctx) {
+		var file struct {
+			ID interface{} `bson:"_id"`
+		}
+		if err := cursor.Decode(&file); err != nil {
+			continue
+		}
+		ids = append(ids, file.ID)
+	}
+
+	for _, id := range ids {
+		if err := d.bucket.Delete(id); err != nil && err != mongo.ErrNoDocuments {
+			return fmt.Errorf("gridfs: could not delete object: %w", err)
+		}
+	}
+	return nil
+}
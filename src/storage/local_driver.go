@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// localDriver stores objects as files under a root directory on local disk.
+type localDriver struct {
+	root string
+}
+
+func init() {
+	Register("localfs", func(config map[string]interface{}) (Driver, error) {
+		root, _ := config["path"].(string)
+		if root == "" {
+			return nil, fmt.Errorf("localfs: %q is required", "path")
+		}
+		if err := os.MkdirAll(root, 0755); err != nil {
+			return nil, fmt.Errorf("localfs: could not create root directory: %w", err)
+		}
+		return &localDriver{root: root}, nil
+	})
+}
+
+func (d *localDriver) resolve(key string) string {
+	return filepath.Join(d.root, filepath.FromSlash(key))
+}
+
+func (d *localDriver) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path := d.resolve(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, fmt.Errorf("localfs: could not create directory: %w", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("localfs: could not create file: %w", err)
+	}
+	defer f.Close()
+
+	size, err := io.Copy(f, r)
+	if err != nil {
+		_ = os.Remove(path)
+		return 0, fmt.Errorf("localfs: could not write file: %w", err)
+	}
+	return size, nil
+}
+
+func (d *localDriver) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(d.resolve(key))
+	if err != nil {
+		return nil, fmt.Errorf("localfs: could not open file: %w", err)
+	}
+	return f, nil
+}
+
+func (d *localDriver) Stat(ctx context.Context, key string) (FileInfo, error) {
+	info, err := os.Stat(d.resolve(key))
+	if err != nil {
+		return FileInfo{}, fmt.Errorf("localfs: could not stat file: %w", err)
+	}
+	return FileInfo{Key: key, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+func (d *localDriver) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(d.resolve(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("localfs: could not delete file: %w", err)
+	}
+	return nil
+}
+
+func (d *localDriver) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	f, err := os.Open(d.resolve(key))
+	if err != nil {
+		return nil, fmt.Errorf("localfs: could not open file: %w", err)
+	}
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("localfs: could not seek file: %w", err)
+	}
+
+	return &limitedReadCloser{r: io.LimitReader(f, length), c: f}, nil
+}
+
+// limitedReadCloser pairs a bounded io.Reader with the underlying file so
+// callers can Close() it like any other object reader.
+type limitedReadCloser struct {
+	r io.Reader
+	c io.Closer
+}
+
+func (l *limitedReadCloser) Read(p []byte) (int, error) { return l.r.Read(p) }
+func (l *limitedReadCloser) Close() error                { return l.c.Close() }
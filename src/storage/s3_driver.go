@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// s3Driver stores objects in a single S3 (or S3-compatible) bucket.
+type s3Driver struct {
+	client *s3.Client
+	bucket string
+}
+
+func init() {
+	Register("s3", func(config map[string]interface{}) (Driver, error) {
+		bucket, _ := config["bucket"].(string)
+		if bucket == "" {
+			return nil, fmt.Errorf("s3: %q is required", "bucket")
+		}
+		region, _ := config["region"].(string)
+		accessKey, _ := config["access_key"].(string)
+		secretKey, _ := config["secret_key"].(string)
+		endpoint, _ := config["endpoint"].(string)
+
+		cfg, err := awsconfig.LoadDefaultConfig(context.Background(),
+			awsconfig.WithRegion(region),
+			awsconfig.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("s3: could not load config: %w", err)
+		}
+
+		client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+			if endpoint != "" {
+				o.BaseEndpoint = aws.String(endpoint)
+				o.UsePathStyle = true
+			}
+		})
+
+		return &s3Driver{client: client, bucket: bucket}, nil
+	})
+}
+
+func (d *s3Driver) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	counter := &countingReader{r: r}
+	_, err := d.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+		Body:   counter,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("s3: could not put object: %w", err)
+	}
+	return counter.n, nil
+}
+
+func (d *s3Driver) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := d.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3: could not get object: %w", err)
+	}
+	return out.Body, nil
+}
+
+func (d *s3Driver) Stat(ctx context.Context, key string) (FileInfo, error) {
+	out, err := d.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return FileInfo{}, fmt.Errorf("s3: could not head object: %w", err)
+	}
+	info := FileInfo{Key: key}
+	if out.ContentLength != nil {
+		info.Size = *out.ContentLength
+	}
+	if out.LastModified != nil {
+		info.ModTime = *out.LastModified
+	}
+	return info, nil
+}
+
+func (d *s3Driver) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	out, err := d.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3: could not get object range: %w", err)
+	}
+	return out.Body, nil
+}
+
+func (d *s3Driver) Delete(ctx context.Context, key string) error {
+	_, err := d.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(d.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("s3: could not delete object: %w", err)
+	}
+	return nil
+}
+
+// countingReader wraps an io.Reader to track how many bytes have been read,
+// since PutObject does not report the size it wrote.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/ncw/swift/v2"
+)
+
+// swiftDriver stores objects in a single OpenStack Swift container.
+type swiftDriver struct {
+	conn      *swift.Connection
+	container string
+}
+
+func init() {
+	Register("swift", func(config map[string]interface{}) (Driver, error) {
+		container, _ := config["container"].(string)
+		if container == "" {
+			return nil, fmt.Errorf("swift: %q is required", "container")
+		}
+
+		conn := &swift.Connection{
+			UserName: asString(config["username"]),
+			ApiKey:   asString(config["api_key"]),
+			AuthUrl:  asString(config["auth_url"]),
+			Domain:   asString(config["domain"]),
+			Tenant:   asString(config["tenant"]),
+			Region:   asString(config["region"]),
+		}
+
+		ctx := context.Background()
+		if err := conn.Authenticate(ctx); err != nil {
+			return nil, fmt.Errorf("swift: could not authenticate: %w", err)
+		}
+
+		if err := conn.ContainerCreate(ctx, container, nil); err != nil {
+			return nil, fmt.Errorf("swift: could not ensure container: %w", err)
+		}
+
+		return &swiftDriver{conn: conn, container: container}, nil
+	})
+}
+
+func asString(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
+
+func (d *swiftDriver) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	_, err := d.conn.ObjectPut(ctx, d.container, key, r, false, "", "", nil)
+	if err != nil {
+		return 0, fmt.Errorf("swift: could not put object: %w", err)
+	}
+	info, _, err := d.conn.Object(ctx, d.container, key)
+	if err != nil {
+		return 0, fmt.Errorf("swift: could not stat object after put: %w", err)
+	}
+	return info.Bytes, nil
+}
+
+func (d *swiftDriver) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, _, err := d.conn.ObjectOpen(ctx, d.container, key, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("swift: could not open object: %w", err)
+	}
+	return rc, nil
+}
+
+func (d *swiftDriver) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	headers := swift.Headers{"Range": fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)}
+	rc, _, err := d.conn.ObjectOpen(ctx, d.container, key, false, headers)
+	if err != nil {
+		return nil, fmt.Errorf("swift: could not open object range: %w", err)
+	}
+	return rc, nil
+}
+
+func (d *swiftDriver) Stat(ctx context.Context, key string) (FileInfo, error) {
+	info, _, err := d.conn.Object(ctx, d.container, key)
+	if err != nil {
+		return FileInfo{}, fmt.Errorf("swift: could not stat object: %w", err)
+	}
+	return FileInfo{Key: key, Size: info.Bytes, ModTime: info.LastModified}, nil
+}
+
+func (d *swiftDriver) Delete(ctx context.Context, key string) error {
+	if err := d.conn.ObjectDelete(ctx, d.container, key); err != nil && err != swift.ObjectNotFound {
+		return fmt.Errorf("swift: could not delete object: %w", err)
+	}
+	return nil
+}
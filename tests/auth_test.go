@@ -2,6 +2,7 @@ package tests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -61,7 +62,7 @@ func TestUserLogin(t *testing.T) {
 		StorageUsed:  0,
 	}
 
-	err := models.CreateUser(user)
+	err := models.CreateUser(context.Background(), user)
 	assert.NoError(t, err)
 
 	// Test user login
@@ -101,7 +102,7 @@ func TestInvalidLogin(t *testing.T) {
 		StorageUsed:  0,
 	}
 
-	err := models.CreateUser(user)
+	err := models.CreateUser(context.Background(), user)
 	assert.NoError(t, err)
 
 	// Test invalid password
@@ -141,7 +142,7 @@ func TestDuplicateUsername(t *testing.T) {
 		StorageUsed:  0,
 	}
 
-	err := models.CreateUser(user)
+	err := models.CreateUser(context.Background(), user)
 	assert.NoError(t, err)
 
 	// Test duplicate username
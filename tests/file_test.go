@@ -2,6 +2,7 @@ package tests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"mime/multipart"
@@ -29,7 +30,7 @@ func createTestUser(t *testing.T) (string, primitive.ObjectID) {
 		StorageUsed:  0,
 	}
 
-	err := models.CreateUser(user)
+	err := models.CreateUser(context.Background(), user)
 	assert.NoError(t, err)
 
 	// Generate token
@@ -211,7 +212,7 @@ func TestStorageLimitExceeded(t *testing.T) {
 		StorageUsed:  0,
 	}
 
-	err := models.CreateUser(user)
+	err := models.CreateUser(context.Background(), user)
 	assert.NoError(t, err)
 
 	token, err := auth.GenerateToken(*user)
@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/yourusername/file-storage-api/src/auth"
+	"github.com/yourusername/file-storage-api/src/helper"
+	"github.com/yourusername/file-storage-api/src/models"
+)
+
+// createLargeQuotaTestUser creates a user with enough storage quota to
+// upload the multi-megabyte fixtures these tests seek within; createTestUser's
+// default 1MB limit is too tight for that.
+func createLargeQuotaTestUser(t *testing.T) string {
+	hashedPassword, _ := auth.HashPassword("testpassword")
+	user := &models.User{
+		Username:     "rangetestuser",
+		Password:     hashedPassword,
+		StorageLimit: 64 * 1024 * 1024, // 64MB
+		StorageUsed:  0,
+	}
+
+	err := models.CreateUser(context.Background(), user)
+	assert.NoError(t, err)
+
+	token, err := auth.GenerateToken(*user)
+	assert.NoError(t, err)
+
+	return token
+}
+
+// largeMP4Fixture builds size bytes of content where byte i always equals
+// byte(i % 256), so every slice's bytes depend on the offset it was taken
+// from and a range served from the wrong offset doesn't match.
+func largeMP4Fixture(size int) []byte {
+	content := make([]byte, size)
+	for i := range content {
+		content[i] = byte(i % 256)
+	}
+	return content
+}
+
+// uploadFixture uploads content as fileName and returns its file ID.
+func uploadFixture(t *testing.T, router http.Handler, token, fileName string, content []byte) string {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", fileName)
+	assert.NoError(t, err)
+	_, err = part.Write(content)
+	assert.NoError(t, err)
+	assert.NoError(t, writer.Close())
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/api/files", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var uploadResponse map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &uploadResponse))
+	return uploadResponse["file"].(map[string]interface{})["id"].(string)
+}
+
+// TestRangeDownloadSeeksWithinLargeFile uploads a multi-megabyte fixture (the
+// kind of size a real MP4 would be) and confirms that seeking into it with
+// Range requests returns exactly the requested bytes, not the whole file.
+func TestRangeDownloadSeeksWithinLargeFile(t *testing.T) {
+	helper.SetupTestDatabase(t)
+	defer helper.CleanupTestDatabase(t)
+
+	router := helper.SetupTestRouter(t)
+	token := createLargeQuotaTestUser(t)
+
+	const fileSize = 5 * 1024 * 1024 // 5MB, larger than any single chunk a naive implementation might buffer
+	content := largeMP4Fixture(fileSize)
+	fileID := uploadFixture(t, router, token, "movie.mp4", content)
+
+	// Seek to a range nowhere near the start or end of the file.
+	start, end := 2_000_000, 2_000_099 // 100 bytes
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/files/"+fileID+"/download", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Range", "bytes="+strconv.Itoa(start)+"-"+strconv.Itoa(end))
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(end)+"/"+strconv.Itoa(fileSize), w.Header().Get("Content-Range"))
+	assert.Equal(t, strconv.Itoa(end-start+1), w.Header().Get("Content-Length"))
+	// Only the requested 100 bytes should have made it over the wire, not
+	// the full 5MB file.
+	assert.Equal(t, end-start+1, w.Body.Len())
+	assert.Equal(t, content[start:end+1], w.Body.Bytes())
+
+	// A suffix range ("last N bytes") should resolve relative to the end
+	// of the file.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/files/"+fileID+"/download", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Range", "bytes=-500")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, 500, w.Body.Len())
+	assert.Equal(t, content[fileSize-500:], w.Body.Bytes())
+}
+
+// TestRangeDownloadIfRange confirms If-Range falls back to the full body
+// when the supplied ETag is stale, and serves a real partial response when
+// it matches the file's current digest-based ETag.
+func TestRangeDownloadIfRange(t *testing.T) {
+	helper.SetupTestDatabase(t)
+	defer helper.CleanupTestDatabase(t)
+
+	router := helper.SetupTestRouter(t)
+	token := createLargeQuotaTestUser(t)
+
+	content := largeMP4Fixture(1024 * 1024)
+	fileID := uploadFixture(t, router, token, "movie.mp4", content)
+
+	// Fetch once to learn the file's current ETag.
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/files/"+fileID+"/download", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	router.ServeHTTP(w, req)
+	currentETag := w.Header().Get("ETag")
+	assert.NotEmpty(t, currentETag)
+
+	// A matching If-Range should still get a real partial response.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/files/"+fileID+"/download", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Range", "bytes=0-99")
+	req.Header.Set("If-Range", currentETag)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, 100, w.Body.Len())
+
+	// A stale If-Range means the range is no longer trustworthy, so the
+	// server should fall back to serving the full, current file.
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/api/files/"+fileID+"/download", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Range", "bytes=0-99")
+	req.Header.Set("If-Range", `"stale-etag"`)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, len(content), w.Body.Len())
+}
+
+// TestRangeDownloadMultiRange confirms a multi-range request comes back as
+// a single multipart/byteranges response whose parts carry exactly the
+// requested spans.
+func TestRangeDownloadMultiRange(t *testing.T) {
+	helper.SetupTestDatabase(t)
+	defer helper.CleanupTestDatabase(t)
+
+	router := helper.SetupTestRouter(t)
+	token := createLargeQuotaTestUser(t)
+
+	content := largeMP4Fixture(1024 * 1024)
+	fileID := uploadFixture(t, router, token, "movie.mp4", content)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/api/files/"+fileID+"/download", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Range", "bytes=0-99,1000-1099")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+
+	mediaType, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/byteranges", mediaType)
+
+	mr := multipart.NewReader(w.Body, params["boundary"])
+
+	expected := [][2]int{{0, 99}, {1000, 1099}}
+	for _, want := range expected {
+		part, err := mr.NextPart()
+		assert.NoError(t, err)
+
+		wantRange := "bytes " + strconv.Itoa(want[0]) + "-" + strconv.Itoa(want[1])
+		assert.True(t, strings.HasPrefix(part.Header.Get("Content-Range"), wantRange))
+
+		got := &bytes.Buffer{}
+		_, err = got.ReadFrom(part)
+		assert.NoError(t, err)
+		assert.Equal(t, content[want[0]:want[1]+1], got.Bytes())
+	}
+
+	_, err = mr.NextPart()
+	assert.Equal(t, "EOF", errString(err))
+}
+
+// errString safely stringifies an error, including a nil one, for assertion
+// messages that want to compare against a sentinel like io.EOF.
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}